@@ -15,11 +15,22 @@
 package cmd
 
 import (
+	"encoding/json"
+	stderrors "errors"
 	"fmt"
 	"io"
+	"math/rand"
 	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/autopp/spexec/pkg/config"
 	"github.com/autopp/spexec/pkg/errors"
+	"github.com/autopp/spexec/pkg/filter"
+	"github.com/autopp/spexec/pkg/matcher"
 	"github.com/autopp/spexec/pkg/matcher/status"
 	"github.com/autopp/spexec/pkg/matcher/stream"
 	"github.com/autopp/spexec/pkg/model"
@@ -27,17 +38,39 @@ import (
 	"github.com/autopp/spexec/pkg/reporter"
 	"github.com/autopp/spexec/pkg/runner"
 	"github.com/autopp/spexec/pkg/spec"
+	"github.com/autopp/spexec/pkg/util"
 	"github.com/mattn/go-isatty"
 	"github.com/spf13/cobra"
 )
 
 type options struct {
-	filenames []string
-	isStdin   bool
-	output    string
-	color     string
-	format    string
-	isStrict  bool
+	filenames   []string
+	isStdin     bool
+	patterns    []string
+	excludes    []string
+	output      string
+	color       string
+	formats     []string
+	targets     []formatTarget
+	isStrict    bool
+	vars        []string
+	varFiles    []string
+	jobs        int
+	unordered   bool
+	failFast    bool
+	maxFailures int
+	order       string
+	seed        *int64
+	randSpecs   bool
+	focus       string
+	skip        string
+	tag         string
+	filter      *filter.Filter
+	failOnFocus bool
+	profile     int
+	showOutput  string
+	dryRun      bool
+	config      *config.Config
 }
 
 const versionFlag = "version"
@@ -45,13 +78,40 @@ const outputFlag = "output"
 const colorFlag = "color"
 const formatFlag = "format"
 const strictFlag = "strict"
+const varFlag = "var"
+const varFileFlag = "var-file"
+const jobsFlag = "jobs"
+const unorderedReportFlag = "unordered-report"
+const failFastFlag = "fail-fast"
+const maxFailuresFlag = "max-failures"
+const orderFlag = "order"
+const shuffleSpecsFlag = "shuffle-specs"
+const focusFlag = "focus"
+const skipFlag = "skip"
+const tagFlag = "tag"
+const failOnFocusFlag = "fail-on-focus"
+const profileFlag = "profile"
+const showOutputFlag = "show-output"
+const dryRunFlag = "dry-run"
+const specPatternFlag = "spec-pattern"
+const excludeFlag = "exclude"
+
+// formatTarget is a format given by --format and the file to write it
+type formatTarget struct {
+	format string
+	path   string
+}
+
+var formats = []string{"simple", "documentation", "json", "junit", "tap", "ndjson", "github"}
 
 // Main is the entrypoint of command line
 func Main(version string, stdin io.Reader, stdout, stderr io.Writer, args []string) error {
 	opts := &options{}
+	configOpts := &configOptions{}
 
 	cmd := &cobra.Command{
 		Use:           "spexec file",
+		Args:          cobra.ArbitraryArgs,
 		SilenceUsage:  true,
 		SilenceErrors: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -62,6 +122,15 @@ func Main(version string, stdin io.Reader, stdout, stderr io.Writer, args []stri
 				return nil
 			}
 
+			c, err := configOpts.load()
+			if err != nil {
+				return err
+			}
+			args, err = opts.applyConfig(cmd, c, nil, args)
+			if err != nil {
+				return err
+			}
+
 			if err := opts.complete(cmd, args); err != nil {
 				return err
 			}
@@ -70,17 +139,39 @@ func Main(version string, stdin io.Reader, stdout, stderr io.Writer, args []stri
 		},
 	}
 
+	configOpts.addFlags(cmd)
 	cmd.Flags().Bool(versionFlag, false, "print version")
 	cmd.Flags().StringVarP(&opts.output, outputFlag, "o", "", "output to file")
 	cmd.Flags().StringVar(&opts.color, colorFlag, "auto", "color output")
 	cmd.RegisterFlagCompletionFunc(colorFlag, func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		return []string{"auto", "always", "never"}, cobra.ShellCompDirectiveDefault
 	})
-	cmd.Flags().StringVar(&opts.format, formatFlag, "simple", "format")
+	cmd.Flags().StringArrayVar(&opts.formats, formatFlag, []string{"simple"}, "format as name[:path] (can be repeated)")
 	cmd.RegisterFlagCompletionFunc(formatFlag, func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-		return []string{"simple", "documentation"}, cobra.ShellCompDirectiveDefault
+		return formats, cobra.ShellCompDirectiveDefault
 	})
-	cmd.Flags().BoolVar(&opts.isStrict, strictFlag, false, "parse spec with strict mode")
+	opts.addSpecFlags(cmd)
+	cmd.Flags().IntVarP(&opts.jobs, jobsFlag, "j", runtime.NumCPU(), "number of tests run in parallel")
+	cmd.Flags().BoolVar(&opts.unordered, unorderedReportFlag, false, "report tests in order of completion instead of order in spec")
+	cmd.Flags().BoolVar(&opts.failFast, failFastFlag, false, "stop running tests after the first failure (same as --max-failures 1)")
+	cmd.Flags().IntVar(&opts.maxFailures, maxFailuresFlag, 0, "stop running tests after the given number of failures (0 means no limit)")
+	cmd.Flags().StringVar(&opts.order, orderFlag, "defined", "order of tests in each spec (defined, random or random:seed)")
+	cmd.RegisterFlagCompletionFunc(orderFlag, func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"defined", "random"}, cobra.ShellCompDirectiveDefault
+	})
+	cmd.Flags().BoolVar(&opts.randSpecs, shuffleSpecsFlag, false, "shuffle order of specs too (requires --order random)")
+	cmd.Flags().StringVar(&opts.focus, focusFlag, "", "run only tests whose name matches to the regexp")
+	cmd.Flags().StringVar(&opts.skip, skipFlag, "", "skip tests whose name matches to the regexp")
+	cmd.Flags().StringVar(&opts.tag, tagFlag, "", "run only tests whose tags satisfy the expression (e.g. 'slow and not network')")
+	cmd.Flags().BoolVar(&opts.failOnFocus, failOnFocusFlag, false, "fail without running tests when some of tests are focused")
+	cmd.Flags().IntVar(&opts.profile, profileFlag, 0, "print the given number of slowest tests")
+	cmd.Flags().StringVar(&opts.showOutput, showOutputFlag, "failures", "print stdout and stderr of tests in formats for human (never, failures or always)")
+	cmd.RegisterFlagCompletionFunc(showOutputFlag, func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"never", "failures", "always"}, cobra.ShellCompDirectiveDefault
+	})
+	cmd.Flags().BoolVar(&opts.dryRun, dryRunFlag, false, "print expanded tests without running them (as JSON with --format json)")
+
+	cmd.AddCommand(newValidateCommand(configOpts), newSchemaCommand(configOpts))
 
 	cmd.SetIn(stdin)
 	cmd.SetOut(stdout)
@@ -90,23 +181,123 @@ func Main(version string, stdin io.Reader, stdout, stderr io.Writer, args []stri
 	return cmd.Execute()
 }
 
+// specFlags are the flags added by addSpecFlags
+var specFlags = []string{strictFlag, varFlag, varFileFlag, specPatternFlag, excludeFlag}
+
+// addSpecFlags adds the flags to load specs
+func (o *options) addSpecFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&o.isStrict, strictFlag, false, "parse spec with strict mode")
+	cmd.Flags().StringArrayVar(&o.vars, varFlag, nil, "define variable as name=value (can be repeated)")
+	cmd.Flags().StringArrayVar(&o.varFiles, varFileFlag, nil, "load variables from YAML or JSON file (can be repeated)")
+	cmd.Flags().StringArrayVar(&o.patterns, specPatternFlag, spec.DefaultSpecPatterns, "pattern of spec filenames searched in directories (can be repeated)")
+	cmd.Flags().StringArrayVar(&o.excludes, excludeFlag, nil, "exclude spec files found in directories or by glob patterns (can be repeated)")
+}
+
 func (o *options) complete(cmd *cobra.Command, args []string) error {
+	if err := o.completeSpecs(args); err != nil {
+		return err
+	}
+
+	if err := validateEnumFlag(colorFlag, o.color, "always", "never", "auto"); err != nil {
+		return err
+	}
+
+	for _, f := range o.formats {
+		format, path, hasPath := strings.Cut(f, ":")
+		if validateEnumFlag(formatFlag, format, formats...) != nil || (hasPath && len(path) == 0) {
+			return fmt.Errorf("invalid --%s flag: %s", formatFlag, f)
+		}
+		o.targets = append(o.targets, formatTarget{format: format, path: path})
+	}
+
+	if o.jobs < 1 {
+		return fmt.Errorf("invalid --%s flag: %d", jobsFlag, o.jobs)
+	}
+
+	if o.maxFailures < 0 {
+		return fmt.Errorf("invalid --%s flag: %d", maxFailuresFlag, o.maxFailures)
+	}
+	if o.failFast && o.maxFailures == 0 {
+		o.maxFailures = 1
+	}
+
+	if err := validateEnumFlag(showOutputFlag, o.showOutput, "never", "failures", "always"); err != nil {
+		return err
+	}
+
+	seed, err := parseOrder(o.order)
+	if err != nil {
+		return err
+	}
+	if o.randSpecs && seed == nil {
+		return fmt.Errorf("--%s flag requires --%s random", shuffleSpecsFlag, orderFlag)
+	}
+	o.seed = seed
+
+	if o.profile < 0 {
+		return fmt.Errorf("invalid --%s flag: %d", profileFlag, o.profile)
+	}
+
+	filterOpts := []filter.Option{}
+	for _, f := range []struct {
+		flag  string
+		value string
+		opt   func(string) filter.Option
+	}{
+		{focusFlag, o.focus, filter.WithFocus},
+		{skipFlag, o.skip, filter.WithSkip},
+		{tagFlag, o.tag, filter.WithTag},
+	} {
+		if len(f.value) == 0 {
+			continue
+		}
+		opt := f.opt(f.value)
+		if _, err := filter.New(opt); err != nil {
+			return fmt.Errorf("invalid --%s flag: %s", f.flag, err)
+		}
+		filterOpts = append(filterOpts, opt)
+	}
+	// all options are already validated
+	o.filter, _ = filter.New(filterOpts...)
+
+	return nil
+}
+
+// completeSpecs sets the spec files found by args and validates the flags to load them
+func (o *options) completeSpecs(args []string) error {
 	if len(args) == 0 {
 		return errors.Errorf(errors.ErrInvalidSpec, "spec is not given")
 	} else if args[0] == "-" {
 		o.filenames = []string{"<stdin>"}
 		o.isStdin = true
 	} else {
-		o.filenames = args
-		o.isStdin = false
-	}
+		for _, f := range []struct {
+			flag     string
+			patterns []string
+		}{
+			{specPatternFlag, o.patterns},
+			{excludeFlag, o.excludes},
+		} {
+			for _, pattern := range f.patterns {
+				if _, err := util.MatchGlob(pattern, ""); err != nil {
+					return fmt.Errorf("invalid --%s flag: %s", f.flag, pattern)
+				}
+			}
+		}
 
-	if err := validateEnumFlag(colorFlag, o.color, "always", "never", "auto"); err != nil {
-		return err
+		filenames, err := spec.Discover(args, o.patterns, o.excludes)
+		if err != nil {
+			return err
+		}
+		o.filenames = filenames
+		o.isStdin = false
 	}
 
-	if err := validateEnumFlag(formatFlag, o.format, "simple", "documentation", "json"); err != nil {
-		return err
+	for _, v := range o.vars {
+		name, _, found := strings.Cut(v, "=")
+		if !found || !model.IsVariableName(name) {
+			return fmt.Errorf("invalid --%s flag: %s", varFlag, v)
+		}
 	}
 
 	return nil
@@ -122,49 +313,184 @@ func validateEnumFlag(flag, value string, validValues ...string) error {
 	return fmt.Errorf("invalid --%s flag: %s", flag, value)
 }
 
-func (o *options) run() error {
+func (o *options) newEnv(p *spec.Parser) (*model.Env, error) {
+	vars := model.Map{}
+	if o.config != nil {
+		for name, value := range o.config.Vars {
+			vars[name] = value
+		}
+	}
+
+	for _, filename := range o.varFiles {
+		v, err := model.NewValidator(filename, o.isStrict)
+		if err != nil {
+			return nil, err
+		}
+
+		fileVars, err := p.ParseVarsFile(v, filename)
+		if err != nil {
+			return nil, err
+		}
+		for name, value := range fileVars {
+			vars[name] = value
+		}
+	}
+
+	for _, v := range o.vars {
+		name, value, _ := strings.Cut(v, "=")
+		vars[name] = value
+	}
+
+	env := model.NewEnv(nil)
+	for name, value := range vars {
+		env.Define(name, value)
+	}
+
+	return env, nil
+}
+
+// loadedSpec is a spec given from command line and its expanded tests
+type loadedSpec struct {
+	filename string
+	tests    []*model.Test
+}
+
+// newParser returns the parser and the matcher registries with the matchers and the defaults in the config
+func (o *options) newParser() (*spec.Parser, *matcher.StatusMatcherRegistry, *matcher.StreamMatcherRegistry, error) {
 	statusMR := status.NewStatusMatcherRegistryWithBuiltins()
 	streamMR := stream.NewStreamMatcherRegistryWithBuiltins()
+	if o.config == nil {
+		return spec.NewParser(statusMR, streamMR), statusMR, streamMR, nil
+	}
 
-	p := spec.NewParser(statusMR, streamMR)
-	specTemplates := []struct {
-		filename      string
-		testTemplates []*template.TestTemplate
-	}{}
-	var err error
-	env := model.NewEnv(nil)
-	if o.isStdin {
-		v, err := model.NewValidator("", o.isStrict)
+	if err := o.config.AddMatchers(statusMR, streamMR); err != nil {
+		return nil, nil, nil, err
+	}
+	p := spec.NewParser(statusMR, streamMR, spec.WithDefaultTimeout(o.config.Timeout), spec.WithDefaultEnv(o.config.Env))
+
+	return p, statusMR, streamMR, nil
+}
+
+// loadSpecs parses and expands all specs given from command line
+func (o *options) loadSpecs() ([]*loadedSpec, error) {
+	p, statusMR, streamMR, err := o.newParser()
+	if err != nil {
+		return nil, err
+	}
+	env, err := o.newEnv(p)
+	if err != nil {
+		return nil, err
+	}
+
+	specs := make([]*loadedSpec, 0, len(o.filenames))
+	for _, filename := range o.filenames {
+		loaded, _, err := o.loadSpec(p, env, filename, statusMR, streamMR)
 		if err != nil {
-			return err
+			return nil, err
+		}
+		specs = append(specs, loaded)
+	}
+
+	return specs, nil
+}
+
+// loadSpec parses and expands the spec of filename.
+// The returned validator has the warnings found in the spec, and is returned also on the error of the spec.
+func (o *options) loadSpec(p *spec.Parser, env *model.Env, filename string, statusMR *matcher.StatusMatcherRegistry, streamMR *matcher.StreamMatcherRegistry) (*loadedSpec, *model.Validator, error) {
+	validatorFilename := filename
+	if o.isStdin {
+		validatorFilename = ""
+	}
+	// the validator of parsing is used also for expansion to report the positions in the spec
+	v, err := model.NewValidator(validatorFilename, o.isStrict)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var specTemplate *template.SpecTemplate
+	if o.isStdin {
+		specTemplate, err = p.ParseStdin(env, v)
+	} else {
+		specTemplate, err = p.ParseFile(env, v, filename)
+	}
+	if err != nil {
+		return nil, v, err
+	}
+
+	tests, err := specTemplate.Expand(env, v, statusMR, streamMR)
+	if err != nil {
+		return nil, v, err
+	}
+	if err := v.Error(); err != nil {
+		return nil, v, err
+	}
+
+	return &loadedSpec{filename: filename, tests: tests}, v, nil
+}
+
+// openTarget returns the file to write the format of target
+func (o *options) openTarget(out *os.File, target formatTarget) (*os.File, error) {
+	if len(target.path) == 0 {
+		return out, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(target.path), 0755); err != nil {
+		return nil, err
+	}
+	return os.Create(target.path)
+}
+
+// reportInvalidSpec writes the violations in err as JSON for the targets of json format, and returns err
+func (o *options) reportInvalidSpec(out *os.File, err error) error {
+	var ve *model.ValidationError
+	if !stderrors.As(err, &ve) {
+		return err
+	}
+
+	for _, target := range o.targets {
+		if target.format != "json" {
+			continue
+		}
+
+		w, openErr := o.openTarget(out, target)
+		if openErr != nil {
+			return openErr
+		}
+		if w != out {
+			defer w.Close()
 		}
-		testTemplates, err := p.ParseStdin(env, v)
+		enc := json.NewEncoder(w)
+		enc.SetEscapeHTML(false)
+		if encodeErr := enc.Encode(map[string]any{"violations": ve.Violations}); encodeErr != nil {
+			return encodeErr
+		}
+	}
+
+	return err
+}
+
+// writeDryRun writes the expanded tests to each target, as JSON for json format and as text for others
+func (o *options) writeDryRun(out *os.File, specs []*loadedSpec) error {
+	for _, target := range o.targets {
+		w, err := o.openTarget(out, target)
 		if err != nil {
 			return err
 		}
-		specTemplates = append(specTemplates, struct {
-			filename      string
-			testTemplates []*template.TestTemplate
-		}{"<stdin>", testTemplates})
-	} else {
-		for _, filename := range o.filenames {
-			v, err := model.NewValidator(filename, o.isStrict)
-			if err != nil {
-				return err
-			}
-			testTemplates, err := p.ParseFile(env, v, filename)
-			if err != nil {
-				return err
-			}
-			specTemplates = append(specTemplates, struct {
-				filename      string
-				testTemplates []*template.TestTemplate
-			}{filename, testTemplates})
+		if w != out {
+			defer w.Close()
+		}
+		if err := writeDryRun(w, target.format, specs); err != nil {
+			return err
 		}
 	}
 
+	return nil
+}
+
+func (o *options) run() error {
 	out := os.Stdout
 	if len(o.output) != 0 {
+		var err error
 		out, err = os.Create(o.output)
 		if err != nil {
 			return err
@@ -172,75 +498,154 @@ func (o *options) run() error {
 		defer out.Close()
 	}
 
-	var colorMode bool
-	switch o.color {
-	case "always":
-		colorMode = true
+	specs, err := o.loadSpecs()
+	if err != nil {
+		return o.reportInvalidSpec(out, err)
+	}
+
+	var showOutput reporter.ShowOutput
+	switch o.showOutput {
 	case "never":
-		colorMode = false
-	case "auto":
-		colorMode = isatty.IsTerminal(out.Fd())
+		showOutput = reporter.ShowOutputNever
+	case "failures":
+		showOutput = reporter.ShowOutputFailures
+	case "always":
+		showOutput = reporter.ShowOutputAlways
 	}
 
-	var formatter reporter.ReportFormatter
-	switch o.format {
-	case "simple":
-		formatter = &reporter.SimpleFormatter{}
-	case "documentation":
-		formatter = &reporter.DocumentationFormatter{}
-	case "json":
-		formatter = &reporter.JSONFormatter{}
+	reporterOpts := []reporter.Option{reporter.WithProfile(o.profile), reporter.WithShowOutput(showOutput)}
+	for _, target := range o.targets {
+		w, err := o.openTarget(out, target)
+		if err != nil {
+			return err
+		}
+		if w != out {
+			defer w.Close()
+		}
+		reporterOpts = append(reporterOpts, reporter.WithTarget(newFormatter(target.format), w, o.colorMode(w)))
 	}
 
-	reporter, err := reporter.New(reporter.WithWriter(out), reporter.WithColor(colorMode), reporter.WithFormatter(formatter))
+	reporter, err := reporter.New(reporterOpts...)
 	if err != nil {
 		return err
 	}
 
-	specs := []struct {
-		filename string
-		tests    []*model.Test
-	}{}
-
-	for _, st := range specTemplates {
-		v, err := model.NewValidator(st.filename, o.isStrict)
-		if err != nil {
-			return err
+	allTests := make([]*model.Test, 0)
+	for _, spec := range specs {
+		allTests = append(allTests, spec.tests...)
+	}
+	if focused := filter.ApplyFocus(allTests); o.failOnFocus && len(focused) != 0 {
+		names := make([]string, 0, len(focused))
+		for _, spec := range specs {
+			for _, t := range spec.tests {
+				if t.Focus {
+					names = append(names, fmt.Sprintf("%q in %s", t.GetName(), spec.filename))
+				}
+			}
 		}
+		return errors.Errorf(errors.ErrInvalidSpec, "focused tests are found: %s", strings.Join(names, ", "))
+	}
+	o.filter.Apply(allTests)
 
-		tests := make([]*model.Test, 0)
-		for _, tt := range st.testTemplates {
-			t, err := tt.Expand(env, v, statusMR, streamMR)
-			if err != nil {
-				return err
-			}
-			tests = append(tests, t)
+	if o.seed != nil {
+		r := rand.New(rand.NewSource(*o.seed))
+		if o.randSpecs {
+			r.Shuffle(len(specs), func(i, j int) {
+				specs[i], specs[j] = specs[j], specs[i]
+			})
 		}
-		err = v.Error()
-		if err != nil {
-			return err
+		for _, spec := range specs {
+			spec.tests = model.ShuffleTests(spec.tests, r)
 		}
-		specs = append(specs, struct {
-			filename string
-			tests    []*model.Test
-		}{filename: st.filename, tests: tests})
 	}
 
-	runner := runner.NewRunner()
-	var results []*model.TestResult
+	if o.dryRun {
+		return o.writeDryRun(out, specs)
+	}
+
+	runner := runner.NewRunner(runner.WithJobs(o.jobs), runner.WithUnorderedReport(o.unordered), runner.WithMaxFailures(o.maxFailures))
+	if err := reporter.OnRunStart(); err != nil {
+		return err
+	}
+
+	start := time.Now()
+	specResults := make([]*model.SpecResult, 0, len(specs))
 	for _, spec := range specs {
-		rs, err := runner.RunTests(spec.filename, spec.tests, reporter)
+		sr, err := runner.RunTests(spec.filename, spec.tests, reporter)
 		if err != nil {
 			return err
 		}
-		results = append(results, rs...)
+		specResults = append(specResults, sr)
+	}
+
+	rr := model.NewRunResult(specResults)
+	rr.Duration = time.Since(start)
+	rr.Seed = o.seed
+	if err := reporter.OnRunComplete(rr); err != nil {
+		return err
+	}
+
+	if !rr.IsSuccess() {
+		return errors.New(errors.ErrTestFailed, "test failed")
 	}
 
-	for _, r := range results {
-		if !r.IsSuccess {
-			return errors.New(errors.ErrTestFailed, "test failed")
+	return nil
+}
+
+// parseOrder returns the seed given by --order, or nil for defined order.
+// A seed is generated when it is omitted.
+func parseOrder(order string) (*int64, error) {
+	if order == "defined" {
+		return nil, nil
+	}
+
+	kind, s, hasSeed := strings.Cut(order, ":")
+	if kind != "random" {
+		return nil, fmt.Errorf("invalid --%s flag: %s", orderFlag, order)
+	}
+
+	var seed int64
+	if hasSeed {
+		var err error
+		if seed, err = strconv.ParseInt(s, 10, 64); err != nil {
+			return nil, fmt.Errorf("invalid --%s flag: %s", orderFlag, order)
 		}
+	} else {
+		// small seed is easy to give from command line
+		seed = rand.New(rand.NewSource(time.Now().UnixNano())).Int63n(100000)
+	}
+
+	return &seed, nil
+}
+
+func newFormatter(format string) reporter.ReportFormatter {
+	switch format {
+	case "simple":
+		return &reporter.SimpleFormatter{}
+	case "documentation":
+		return &reporter.DocumentationFormatter{}
+	case "json":
+		return &reporter.JSONFormatter{}
+	case "junit":
+		return &reporter.JUnitFormatter{}
+	case "tap":
+		return &reporter.TAPFormatter{}
+	case "ndjson":
+		return &reporter.NDJSONFormatter{}
+	case "github":
+		return &reporter.GitHubFormatter{StepSummary: os.Getenv("GITHUB_STEP_SUMMARY")}
 	}
 
 	return nil
 }
+
+func (o *options) colorMode(out *os.File) bool {
+	switch o.color {
+	case "always":
+		return true
+	case "never":
+		return false
+	}
+
+	return isatty.IsTerminal(out.Fd())
+}
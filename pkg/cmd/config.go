@@ -0,0 +1,106 @@
+// Copyright (C) 2021-2023	 Akira Tanimura (@autopp)
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/autopp/spexec/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+const configFlag = "config"
+const noConfigFlag = "no-config"
+
+// configOptions is the options to find the config file, which are shared by all commands
+type configOptions struct {
+	filename string
+	noConfig bool
+}
+
+func (co *configOptions) addFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVar(&co.filename, configFlag, "", "use the config file instead of "+config.Filename+" found from the working directory upward")
+	cmd.PersistentFlags().BoolVar(&co.noConfig, noConfigFlag, false, "do not use config file")
+}
+
+// load returns the config given by --config or found from the working directory, or nil when it is not used
+func (co *configOptions) load() (*config.Config, error) {
+	if co.noConfig {
+		return nil, nil
+	}
+
+	filename := co.filename
+	if len(filename) == 0 {
+		wd, err := os.Getwd()
+		if err != nil {
+			return nil, err
+		}
+		filename, err = config.Find(wd)
+		if err != nil {
+			return nil, err
+		}
+		if len(filename) == 0 {
+			return nil, nil
+		}
+	}
+
+	return config.Load(filename)
+}
+
+// applyConfig sets the flags in c which are not given from command line, and returns the specs in c when args is empty.
+// When flagNames is given, the flags out of them are ignored.
+func (o *options) applyConfig(cmd *cobra.Command, c *config.Config, flagNames []string, args []string) ([]string, error) {
+	o.config = c
+	if c == nil {
+		return args, nil
+	}
+
+	for _, flag := range c.Flags {
+		if flagNames != nil && !contains(flagNames, flag.Name) {
+			continue
+		}
+
+		f := cmd.Flags().Lookup(flag.Name)
+		if f == nil || contains([]string{versionFlag, configFlag, noConfigFlag}, flag.Name) {
+			return nil, fmt.Errorf("invalid flag in %s: %s", c.Filename, flag.Name)
+		}
+		if f.Changed {
+			continue
+		}
+
+		for _, value := range flag.Values {
+			if err := cmd.Flags().Set(flag.Name, value); err != nil {
+				return nil, fmt.Errorf("invalid --%s flag in %s: %s", flag.Name, c.Filename, value)
+			}
+		}
+	}
+
+	if len(args) == 0 {
+		return c.Specs, nil
+	}
+
+	return args, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,141 @@
+// Copyright (C) 2021-2023	 Akira Tanimura (@autopp)
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Wing924/shellwords"
+	"github.com/autopp/spexec/pkg/model"
+)
+
+// maxStdinPreview is the max length in bytes of stdin printed by --dry-run with text
+const maxStdinPreview = 40
+
+// dryRunTest is the description of an expanded test printed by --dry-run
+type dryRunTest struct {
+	Name          string         `json:"name"`
+	Line          int            `json:"line,omitempty"`
+	Command       []string       `json:"command"`
+	Dir           string         `json:"dir"`
+	Env           []dryRunEnvVar `json:"env"`
+	Stdin         string         `json:"stdin,omitempty"`
+	Timeout       float64        `json:"timeout,omitempty"`
+	Expect        model.Map      `json:"expect"`
+	SkipReason    string         `json:"skip,omitempty"`
+	PendingReason string         `json:"pending,omitempty"`
+}
+
+type dryRunEnvVar struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type dryRunSpec struct {
+	Filename string        `json:"file"`
+	Tests    []*dryRunTest `json:"tests"`
+}
+
+func newDryRunTest(t *model.Test) *dryRunTest {
+	command := make([]string, len(t.Command))
+	for i, c := range t.Command {
+		command[i] = c.String()
+	}
+
+	env := make([]dryRunEnvVar, len(t.Env))
+	for i, v := range t.Env {
+		env[i] = dryRunEnvVar{Name: v.Name, Value: v.Value}
+	}
+
+	return &dryRunTest{
+		Name:          t.GetName(),
+		Line:          t.Line,
+		Command:       command,
+		Dir:           t.Dir,
+		Env:           env,
+		Stdin:         string(t.Stdin),
+		Timeout:       t.Timeout.Seconds(),
+		Expect:        t.Expect,
+		SkipReason:    t.SkipReason,
+		PendingReason: t.PendingReason,
+	}
+}
+
+// writeDryRun writes the expanded tests of specs without running them
+func writeDryRun(w io.Writer, format string, specs []*loadedSpec) error {
+	drySpecs := make([]*dryRunSpec, len(specs))
+	for i, spec := range specs {
+		tests := make([]*dryRunTest, len(spec.tests))
+		for j, t := range spec.tests {
+			tests[j] = newDryRunTest(t)
+		}
+		drySpecs[i] = &dryRunSpec{Filename: spec.filename, Tests: tests}
+	}
+
+	if format == "json" {
+		enc := json.NewEncoder(w)
+		enc.SetEscapeHTML(false)
+		return enc.Encode(map[string]any{"specs": drySpecs})
+	}
+
+	for i, spec := range drySpecs {
+		fmt.Fprintln(w, spec.Filename)
+		for j, t := range spec.Tests {
+			fmt.Fprintf(w, "  %s\n", t.Name)
+			fmt.Fprintf(w, "    command: %s\n", shellwords.Join(t.Command))
+			fmt.Fprintf(w, "    dir: %s\n", t.Dir)
+			if len(t.Env) != 0 {
+				env := make([]string, len(t.Env))
+				for k, v := range t.Env {
+					env[k] = v.Name + "=" + shellwords.Escape(v.Value)
+				}
+				fmt.Fprintf(w, "    env: %s\n", strings.Join(env, " "))
+			}
+			if len(t.Stdin) != 0 {
+				stdin := t.Stdin
+				if len(stdin) > maxStdinPreview {
+					n := maxStdinPreview
+					// do not split a multibyte character
+					for n > 0 && !utf8.RuneStart(stdin[n]) {
+						n--
+					}
+					stdin = stdin[:n] + "..."
+				}
+				fmt.Fprintf(w, "    stdin: %q\n", stdin)
+			}
+			if timeout := specs[i].tests[j].Timeout; timeout != 0 {
+				fmt.Fprintf(w, "    timeout: %s\n", timeout)
+			}
+			if len(t.Expect) != 0 {
+				expect, err := json.Marshal(t.Expect)
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(w, "    expect: %s\n", expect)
+			}
+			if len(t.PendingReason) != 0 {
+				fmt.Fprintf(w, "    pending: %s\n", t.PendingReason)
+			} else if len(t.SkipReason) != 0 {
+				fmt.Fprintf(w, "    skip: %s\n", t.SkipReason)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,46 @@
+// Copyright (C) 2021-2023	 Akira Tanimura (@autopp)
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+
+	"github.com/spf13/cobra"
+)
+
+func newSchemaCommand(configOpts *configOptions) *cobra.Command {
+	return &cobra.Command{
+		Use:   "schema",
+		Short: "print JSON Schema of spec",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := configOpts.load()
+			if err != nil {
+				return err
+			}
+			// only the matchers in the config are used
+			opts := &options{config: c}
+			p, _, _, err := opts.newParser()
+			if err != nil {
+				return err
+			}
+
+			enc := json.NewEncoder(cmd.OutOrStdout())
+			enc.SetEscapeHTML(false)
+			enc.SetIndent("", "  ")
+			return enc.Encode(p.Schema())
+		},
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright (C) 2021-2023	 Akira Tanimura (@autopp)
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"io"
+
+	"github.com/autopp/spexec/pkg/errors"
+	"github.com/autopp/spexec/pkg/model"
+	"github.com/spf13/cobra"
+)
+
+type validateOptions struct {
+	options
+	format string
+}
+
+func newValidateCommand(configOpts *configOptions) *cobra.Command {
+	opts := &validateOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "validate file...",
+		Short: "validate specs without running them",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := configOpts.load()
+			if err != nil {
+				return err
+			}
+			args, err = opts.applyConfig(cmd, c, specFlags, args)
+			if err != nil {
+				return err
+			}
+
+			if err := opts.complete(args); err != nil {
+				return err
+			}
+
+			return opts.validate(cmd.OutOrStdout())
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.format, formatFlag, "text", "format of violations and warnings (text or json)")
+	cmd.RegisterFlagCompletionFunc(formatFlag, func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"text", "json"}, cobra.ShellCompDirectiveDefault
+	})
+	opts.addSpecFlags(cmd)
+
+	return cmd
+}
+
+func (o *validateOptions) complete(args []string) error {
+	if err := o.completeSpecs(args); err != nil {
+		return err
+	}
+
+	return validateEnumFlag(formatFlag, o.format, "text", "json")
+}
+
+// validate loads all specs and reports the violations and the warnings found in them.
+// Unlike run, it does not stop at the first invalid spec or the spec which cannot be loaded.
+func (o *validateOptions) validate(w io.Writer) error {
+	p, statusMR, streamMR, err := o.newParser()
+	if err != nil {
+		return err
+	}
+	env, err := o.newEnv(p)
+	if err != nil {
+		return err
+	}
+
+	violations := make([]*model.Violation, 0)
+	warnings := make([]*model.Violation, 0)
+	for _, filename := range o.filenames {
+		_, v, err := o.loadSpec(p, env, filename, statusMR, streamMR)
+		if err != nil {
+			var ve *model.ValidationError
+			if stderrors.As(err, &ve) {
+				violations = append(violations, ve.Violations...)
+			} else {
+				// the errors except violations (e.g. syntax error of YAML) are also reported as violations of the spec
+				violations = append(violations, &model.Violation{Filename: filename, Path: "$", Message: err.Error()})
+			}
+		}
+		if v != nil {
+			warnings = append(warnings, v.Warnings()...)
+		}
+	}
+
+	if o.format == "json" {
+		enc := json.NewEncoder(w)
+		enc.SetEscapeHTML(false)
+		if err := enc.Encode(map[string]any{"violations": violations, "warnings": warnings}); err != nil {
+			return err
+		}
+	} else {
+		for _, violation := range violations {
+			if violation.Line == 0 {
+				fmt.Fprintf(w, "%s: %s\n", violation.Filename, violation.String())
+			} else {
+				fmt.Fprintln(w, violation.String())
+			}
+		}
+		for _, warning := range warnings {
+			fmt.Fprintf(w, "warning: %s\n", warning.String())
+		}
+		fmt.Fprintf(w, "%d specs, %d violations, %d warnings\n", len(o.filenames), len(violations), len(warnings))
+	}
+
+	if len(violations) != 0 {
+		return errors.Errorf(errors.ErrInvalidSpec, "%d violations are found", len(violations))
+	}
+
+	return nil
+}
@@ -0,0 +1,247 @@
+// Copyright (C) 2021-2023	 Akira Tanimura (@autopp)
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/autopp/spexec/pkg/errors"
+	"github.com/autopp/spexec/pkg/matcher"
+	"github.com/autopp/spexec/pkg/model"
+	"github.com/autopp/spexec/pkg/spec"
+	"github.com/autopp/spexec/pkg/util"
+)
+
+// Filename is the name of config file searched from the working directory upward
+const Filename = ".spexec.yaml"
+
+// Config is the project configuration
+type Config struct {
+	Filename       string
+	Flags          []*Flag
+	Specs          []string
+	Timeout        time.Duration
+	Env            []util.StringVar
+	Vars           model.Map
+	StatusMatchers model.Map
+	StreamMatchers model.Map
+	// validator of loading, which is used also for matcher definitions to report the positions in the file
+	v *model.Validator
+}
+
+// Flag is the default values of a command line flag
+type Flag struct {
+	Name   string
+	Values []string
+}
+
+// Find returns the config file in dir or its nearest ancestor, or empty string when it is not found
+func Find(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+
+	for {
+		filename := filepath.Join(dir, Filename)
+		if info, err := os.Stat(filename); err == nil && !info.IsDir() {
+			return filename, nil
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+		dir = parent
+	}
+}
+
+// Load loads the config file.
+// Spec paths in it are resolved from the directory of the file.
+func Load(filename string) (*Config, error) {
+	v, err := model.NewValidator(filename, true)
+	if err != nil {
+		return nil, err
+	}
+
+	x, err := spec.DecodeYAMLFile(v, filename)
+	if err != nil {
+		return nil, err
+	}
+
+	m, ok := v.MustBeMap(x)
+	if !ok {
+		return nil, v.Error()
+	}
+	v.MustContainOnly(m, "flags", "specs", "timeout", "env", "vars", "matchers")
+
+	c := &Config{Filename: filename, Flags: make([]*Flag, 0), Specs: make([]string, 0), Vars: model.Map{}, StatusMatchers: model.Map{}, StreamMatchers: model.Map{}, v: v}
+
+	v.MayHaveMap(m, "flags", func(flags model.Map) {
+		names := make([]string, 0, len(flags))
+		for name := range flags {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			v.InField(name, func() {
+				if values, ok := loadFlagValues(v, flags[name]); ok {
+					c.Flags = append(c.Flags, &Flag{Name: name, Values: values})
+				}
+			})
+		}
+	})
+
+	v.MayHaveSeq(m, "specs", func(specs model.Seq) {
+		v.ForInSeq(specs, func(i int, x any) bool {
+			path, ok := v.MustBeString(x)
+			if ok {
+				if !filepath.IsAbs(path) {
+					path = util.RelativePath(filepath.Join(v.GetDir(), path))
+				}
+				c.Specs = append(c.Specs, path)
+			}
+			return ok
+		})
+	})
+
+	c.Timeout, _, _ = v.MayHaveDuration(m, "timeout")
+	c.Env, _, _ = v.MayHaveEnvSeq(m, "env")
+
+	v.MayHaveMap(m, "vars", func(vars model.Map) {
+		for name, value := range vars {
+			v.InField(name, func() {
+				if v.MustBeVariableName(name) {
+					c.Vars[name] = value
+				}
+			})
+		}
+	})
+
+	v.MayHaveMap(m, "matchers", func(matchers model.Map) {
+		v.MustContainOnly(matchers, "status", "stream")
+		v.MayHaveMap(matchers, "status", func(defs model.Map) {
+			c.StatusMatchers = defs
+		})
+		v.MayHaveMap(matchers, "stream", func(defs model.Map) {
+			c.StreamMatchers = defs
+		})
+	})
+
+	if err := v.Error(); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// loadFlagValues returns the values of flag given as a scalar or a seq of scalars
+func loadFlagValues(v *model.Validator, x any) ([]string, bool) {
+	toString := func(x any) (string, bool) {
+		switch x.(type) {
+		case string, bool, int, float64:
+			return fmt.Sprint(x), true
+		}
+		v.AddViolation("should be string, bool or number, but is %s", model.TypeNameOf(x))
+		return "", false
+	}
+
+	if seq, ok := v.MayBeSeq(x); ok {
+		values := make([]string, 0, len(seq))
+		ok := v.ForInSeq(seq, func(i int, x any) bool {
+			value, ok := toString(x)
+			values = append(values, value)
+			return ok
+		})
+		return values, ok
+	}
+
+	value, ok := toString(x)
+	return []string{value}, ok
+}
+
+// AddMatchers adds the matchers defined in the config to the registries.
+// A defined matcher takes no parameter and works as its definition.
+func (c *Config) AddMatchers(statusMR *matcher.StatusMatcherRegistry, streamMR *matcher.StreamMatcherRegistry) error {
+	v := c.v
+	if err := addMatchers(c.Filename, statusMR, c.StatusMatchers); err != nil {
+		return err
+	}
+	if err := addMatchers(c.Filename, streamMR, c.StreamMatchers); err != nil {
+		return err
+	}
+
+	// definitions are parsed here to report their violations with the path in the config
+	v.InField("matchers", func() {
+		parseDefinitions(v, "status", statusMR, c.StatusMatchers)
+		parseDefinitions(v, "stream", streamMR, c.StreamMatchers)
+	})
+
+	return v.Error()
+}
+
+func addMatchers[T any](filename string, r *matcher.MatcherParserRegistry[T], defs model.Map) error {
+	for _, name := range sortedNames(defs) {
+		if err := r.AddWithDefault(name, newDefinedMatcherParser[T](defs[name]), nil, matcher.WithParamSchema(model.Map{"type": "null"})); err != nil {
+			return errors.Errorf(errors.ErrInvalidSpec, "%s: matcher %s is already defined", util.RelativePath(filename), name)
+		}
+	}
+
+	return nil
+}
+
+func parseDefinitions[T any](v *model.Validator, kind string, r *matcher.MatcherParserRegistry[T], defs model.Map) {
+	v.InField(kind, func() {
+		for _, name := range sortedNames(defs) {
+			v.InField(name, func() {
+				r.ParseMatcher(v, defs[name])
+			})
+		}
+	})
+}
+
+func newDefinedMatcherParser[T any](definition any) matcher.MatcherParser[T] {
+	parsing := false
+	return func(v *model.Validator, r *matcher.MatcherParserRegistry[T], x any) model.Matcher[T] {
+		if x != nil {
+			v.AddViolation("should have no parameter")
+			return nil
+		}
+
+		if parsing {
+			v.AddViolation("is defined recursively")
+			return nil
+		}
+		parsing = true
+		defer func() { parsing = false }()
+
+		return r.ParseMatcher(v, definition)
+	}
+}
+
+func sortedNames(m model.Map) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
@@ -0,0 +1,88 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/autopp/spexec/pkg/matcher/status"
+	"github.com/autopp/spexec/pkg/matcher/stream"
+	"github.com/autopp/spexec/pkg/model"
+	"github.com/autopp/spexec/pkg/util"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Find()", func() {
+	It("returns the config file in the nearest ancestor directory", func() {
+		expected, _ := filepath.Abs(filepath.Join("testdata", Filename))
+		Expect(Find(filepath.Join("testdata", "sub"))).To(Equal(expected))
+	})
+
+	It("returns empty string when config file is not found", func() {
+		Expect(Find(GinkgoT().TempDir())).To(BeEmpty())
+	})
+})
+
+var _ = Describe("Load()", func() {
+	It("returns the config in the file", func() {
+		c, err := Load(filepath.Join("testdata", Filename))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c.Flags).To(Equal([]*Flag{
+			{Name: "format", Values: []string{"documentation", "junit:report.xml"}},
+			{Name: "strict", Values: []string{"true"}},
+		}))
+		Expect(c.Specs).To(Equal([]string{filepath.Join("testdata", "specs")}))
+		Expect(c.Timeout).To(Equal(5 * time.Second))
+		Expect(c.Env).To(Equal([]util.StringVar{{Name: "LANG", Value: "C"}}))
+		Expect(c.Vars).To(Equal(model.Map{"greeting": "hello"}))
+	})
+
+	It("returns error with the position of the violation", func() {
+		_, err := Load(filepath.Join("testdata", "invalid.yaml"))
+		Expect(err).To(MatchError("testdata/invalid.yaml:4:7: $.flags.format[1]: should be string, bool or number, but is map"))
+	})
+
+	It("returns error when the name of var is invalid", func() {
+		filename := filepath.Join(GinkgoT().TempDir(), Filename)
+		Expect(os.WriteFile(filename, []byte("vars:\n  invalid-name: 1\n"), 0644)).To(Succeed())
+		c, err := Load(filename)
+		Expect(c).To(BeNil())
+		Expect(err).To(MatchError(ContainSubstring("$.vars.invalid-name: variable name should be match to")))
+	})
+
+	It("returns error when the file does not exist", func() {
+		_, err := Load(filepath.Join("testdata", "unknown.yaml"))
+		Expect(err).To(MatchError(os.ErrNotExist))
+	})
+})
+
+var _ = Describe("Config", func() {
+	Describe("AddMatchers()", func() {
+		It("adds the matchers which work as their definitions", func() {
+			c, _ := Load(filepath.Join("testdata", Filename))
+			statusMR := status.NewStatusMatcherRegistryWithBuiltins()
+			streamMR := stream.NewStreamMatcherRegistryWithBuiltins()
+			Expect(c.AddMatchers(statusMR, streamMR)).To(Succeed())
+
+			v, _ := model.NewValidator("", true)
+			failed := statusMR.ParseMatcher(v, "failed")
+			notGreeting := streamMR.ParseMatcher(v, "notGreeting")
+			Expect(v.Error()).NotTo(HaveOccurred())
+			matched, _, _ := failed.Match(1)
+			Expect(matched).To(BeTrue())
+			matched, _, _ = notGreeting.Match([]byte("hello\n"))
+			Expect(matched).To(BeFalse())
+
+			streamMR.ParseMatcher(v, model.Map{"greeting": "hello"})
+			Expect(v.Error()).To(MatchError("$.greeting: should have no parameter"))
+		})
+
+		It("returns error when the definition is recursive", func() {
+			c, _ := Load(filepath.Join("testdata", "invalid_matcher.yaml"))
+			err := c.AddMatchers(status.NewStatusMatcherRegistryWithBuiltins(), stream.NewStreamMatcherRegistryWithBuiltins())
+			Expect(err).To(MatchError("testdata/invalid_matcher.yaml:4:7: $.matchers.stream.loop.not.loop.not.loop: is defined recursively"))
+		})
+	})
+})
@@ -30,12 +30,15 @@ import (
 )
 
 type ExecResult struct {
-	Stdout    []byte
-	Stderr    []byte
-	Status    int
-	Signal    os.Signal
-	IsTimeout bool
-	Err       error
+	Stdout     []byte
+	Stderr     []byte
+	Status     int
+	Signal     os.Signal
+	IsTimeout  bool
+	Err        error
+	Elapsed    time.Duration
+	UserTime   time.Duration
+	SystemTime time.Duration
 }
 
 type Exec struct {
@@ -107,6 +110,13 @@ func New(command []string, dir string, stdin []byte, env []util.StringVar, opts
 }
 
 func (e *Exec) Run() *ExecResult {
+	start := time.Now()
+	r := e.run()
+	r.Elapsed = time.Since(start)
+	return r
+}
+
+func (e *Exec) run() *ExecResult {
 	cmd := exec.Command(e.Command[0], e.Command[1:]...)
 	cmd.Dir = e.Dir
 	cmd.Stdin = bytes.NewReader(e.Stdin)
@@ -147,17 +157,21 @@ func (e *Exec) Run() *ExecResult {
 
 	if ps.Exited() {
 		return &ExecResult{
-			Stdout: stdout.Bytes(),
-			Stderr: stderr.Bytes(),
-			Status: es.GetExitCode(),
+			Stdout:     stdout.Bytes(),
+			Stderr:     stderr.Bytes(),
+			Status:     es.GetExitCode(),
+			UserTime:   ps.UserTime(),
+			SystemTime: ps.SystemTime(),
 		}
 	}
 
 	if es.IsTimedOut() {
 		return &ExecResult{
-			Stdout:    stdout.Bytes(),
-			Stderr:    stderr.Bytes(),
-			IsTimeout: true,
+			Stdout:     stdout.Bytes(),
+			Stderr:     stderr.Bytes(),
+			IsTimeout:  true,
+			UserTime:   ps.UserTime(),
+			SystemTime: ps.SystemTime(),
 		}
 	}
 
@@ -180,8 +194,10 @@ func (e *Exec) Run() *ExecResult {
 	}
 
 	return &ExecResult{
-		Stdout: stdout.Bytes(),
-		Stderr: stderr.Bytes(),
-		Signal: ws.Signal(),
+		Stdout:     stdout.Bytes(),
+		Stderr:     stderr.Bytes(),
+		Signal:     ws.Signal(),
+		UserTime:   ps.UserTime(),
+		SystemTime: ps.SystemTime(),
 	}
 }
@@ -131,6 +131,7 @@ var _ = Describe("Exec", func() {
 				}
 			}
 			Expect(er.IsTimeout).To(Equal(isTimeout))
+			Expect(er.Elapsed).To(BeNumerically(">", 0))
 		},
 		Entry("with `echo -n 42`",
 			&Exec{
@@ -165,4 +166,10 @@ var _ = Describe("Exec", func() {
 			false, 0, "", "", "", true,
 		),
 	)
+	It("records elapsed time", func() {
+		e := &Exec{Command: []string{"sleep", "0.1"}, Timeout: defaultTimeout}
+		er := e.Run()
+
+		Expect(er.Elapsed).To(BeNumerically(">=", 100*time.Millisecond))
+	})
 })
@@ -0,0 +1,121 @@
+// Copyright (C) 2021-2023	 Akira Tanimura (@autopp)
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filter
+
+import (
+	"regexp"
+
+	"github.com/autopp/spexec/pkg/model"
+)
+
+// Filter selects tests to be run by their names and tags
+type Filter struct {
+	focus *regexp.Regexp
+	skip  *regexp.Regexp
+	tag   TagExpr
+}
+
+// Option is functional option of New
+type Option func(f *Filter) error
+
+// WithFocus is a option of New to run only tests whose name matches to pattern
+func WithFocus(pattern string) Option {
+	return func(f *Filter) (err error) {
+		f.focus, err = regexp.Compile(pattern)
+		return
+	}
+}
+
+// WithSkip is a option of New to skip tests whose name matches to pattern
+func WithSkip(pattern string) Option {
+	return func(f *Filter) (err error) {
+		f.skip, err = regexp.Compile(pattern)
+		return
+	}
+}
+
+// WithTag is a option of New to run only tests whose tags satisfy the tag expression
+func WithTag(expr string) Option {
+	return func(f *Filter) (err error) {
+		f.tag, err = ParseTagExpr(expr)
+		return
+	}
+}
+
+// New returns a new Filter
+func New(opts ...Option) (*Filter, error) {
+	f := &Filter{}
+	for _, o := range opts {
+		if err := o(f); err != nil {
+			return nil, err
+		}
+	}
+
+	return f, nil
+}
+
+// Apply marks tests which are filtered out as skipped.
+// Names are matched after template expansion and include the names of groups.
+func (f *Filter) Apply(tests []*model.Test) {
+	for _, t := range tests {
+		if t.IsSkipped() {
+			continue
+		}
+
+		if reason, ok := f.reasonToSkip(t); ok {
+			t.Skip(reason)
+		}
+	}
+}
+
+func (f *Filter) reasonToSkip(t *model.Test) (string, bool) {
+	name := t.GetName()
+	if f.focus != nil && !f.focus.MatchString(name) {
+		return "not matched to --focus " + f.focus.String(), true
+	}
+
+	if f.skip != nil && f.skip.MatchString(name) {
+		return "matched to --skip " + f.skip.String(), true
+	}
+
+	if f.tag != nil && !f.tag.Match(t) {
+		return "not matched to --tag " + f.tag.String(), true
+	}
+
+	return "", false
+}
+
+// ApplyFocus marks tests which are not focused as skipped when some of tests are focused.
+// It returns the focused tests.
+func ApplyFocus(tests []*model.Test) []*model.Test {
+	focused := make([]*model.Test, 0)
+	for _, t := range tests {
+		if t.Focus {
+			focused = append(focused, t)
+		}
+	}
+
+	if len(focused) == 0 {
+		return focused
+	}
+
+	for _, t := range tests {
+		if !t.Focus && !t.IsSkipped() {
+			t.Skip("not focused")
+		}
+	}
+
+	return focused
+}
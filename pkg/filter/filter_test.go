@@ -0,0 +1,93 @@
+package filter
+
+import (
+	"github.com/autopp/spexec/pkg/model"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Filter", func() {
+	Describe("Apply()", func() {
+		var tests []*model.Test
+
+		JustBeforeEach(func() {
+			group := &model.Group{Name: "group"}
+			tests = []*model.Test{
+				{Name: "fast test", Tags: []string{"fast"}},
+				{Name: "slow test", Tags: []string{"slow"}, Group: group},
+				{Name: "other test", SkipReason: "already skipped"},
+			}
+		})
+
+		skipReasons := func() []string {
+			reasons := make([]string, len(tests))
+			for i, t := range tests {
+				reasons[i] = t.SkipReason
+			}
+			return reasons
+		}
+
+		It("skips tests not matched to focus with the names of groups", func() {
+			f, err := New(WithFocus("^group "))
+			Expect(err).NotTo(HaveOccurred())
+			f.Apply(tests)
+			Expect(skipReasons()).To(Equal([]string{"not matched to --focus ^group ", "", "already skipped"}))
+		})
+
+		It("skips tests matched to skip", func() {
+			f, err := New(WithSkip("fast"))
+			Expect(err).NotTo(HaveOccurred())
+			f.Apply(tests)
+			Expect(skipReasons()).To(Equal([]string{"matched to --skip fast", "", "already skipped"}))
+		})
+
+		It("skips tests not matched to tag", func() {
+			f, err := New(WithTag("not slow"))
+			Expect(err).NotTo(HaveOccurred())
+			f.Apply(tests)
+			Expect(skipReasons()).To(Equal([]string{"", "not matched to --tag (not slow)", "already skipped"}))
+		})
+
+		It("does not skip any tests without options", func() {
+			f, err := New()
+			Expect(err).NotTo(HaveOccurred())
+			f.Apply(tests)
+			Expect(skipReasons()).To(Equal([]string{"", "", "already skipped"}))
+		})
+	})
+
+	Describe("New()", func() {
+		It("returns error with invalid regexp", func() {
+			_, err := New(WithFocus("("))
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("returns error with invalid tag expression", func() {
+			_, err := New(WithTag("and"))
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("ApplyFocus()", func() {
+		It("skips tests which are not focused", func() {
+			tests := []*model.Test{
+				{Name: "test1", Focus: true},
+				{Name: "test2"},
+				{Name: "test3", PendingReason: "not implemented"},
+			}
+
+			Expect(ApplyFocus(tests)).To(Equal([]*model.Test{tests[0]}))
+			Expect(tests[0].IsSkipped()).To(BeFalse())
+			Expect(tests[1].SkipReason).To(Equal("not focused"))
+			Expect(tests[2].SkipReason).To(BeEmpty())
+		})
+
+		It("does nothing when no tests are focused", func() {
+			tests := []*model.Test{{Name: "test1"}, {Name: "test2"}}
+
+			Expect(ApplyFocus(tests)).To(BeEmpty())
+			Expect(tests[0].IsSkipped()).To(BeFalse())
+			Expect(tests[1].IsSkipped()).To(BeFalse())
+		})
+	})
+})
@@ -0,0 +1,196 @@
+// Copyright (C) 2021-2023	 Akira Tanimura (@autopp)
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/autopp/spexec/pkg/model"
+)
+
+// TagExpr is a boolean expression over tags of a test
+type TagExpr interface {
+	Match(t *model.Test) bool
+	String() string
+}
+
+type tagRef struct {
+	name string
+}
+
+func (e *tagRef) Match(t *model.Test) bool {
+	return t.HasTag(e.name)
+}
+
+func (e *tagRef) String() string {
+	return e.name
+}
+
+type notExpr struct {
+	operand TagExpr
+}
+
+func (e *notExpr) Match(t *model.Test) bool {
+	return !e.operand.Match(t)
+}
+
+func (e *notExpr) String() string {
+	return "(not " + e.operand.String() + ")"
+}
+
+type andExpr struct {
+	lhs, rhs TagExpr
+}
+
+func (e *andExpr) Match(t *model.Test) bool {
+	return e.lhs.Match(t) && e.rhs.Match(t)
+}
+
+func (e *andExpr) String() string {
+	return "(" + e.lhs.String() + " and " + e.rhs.String() + ")"
+}
+
+type orExpr struct {
+	lhs, rhs TagExpr
+}
+
+func (e *orExpr) Match(t *model.Test) bool {
+	return e.lhs.Match(t) || e.rhs.Match(t)
+}
+
+func (e *orExpr) String() string {
+	return "(" + e.lhs.String() + " or " + e.rhs.String() + ")"
+}
+
+/*
+ParseTagExpr parses tag expression like "(slow or network) and not flaky".
+
+Grammar:
+
+	expr    = and ("or" and)*
+	and     = unary ("and" unary)*
+	unary   = "not" unary | primary
+	primary = TAG | "(" expr ")"
+*/
+func ParseTagExpr(src string) (TagExpr, error) {
+	p := &tagExprParser{tokens: tokenizeTagExpr(src)}
+	if len(p.tokens) == 0 {
+		return nil, fmt.Errorf("tag expression is empty")
+	}
+
+	e, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q in tag expression", p.tokens[p.pos])
+	}
+
+	return e, nil
+}
+
+func tokenizeTagExpr(src string) []string {
+	src = strings.ReplaceAll(src, "(", " ( ")
+	src = strings.ReplaceAll(src, ")", " ) ")
+	return strings.Fields(src)
+}
+
+type tagExprParser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *tagExprParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+
+	return ""
+}
+
+func (p *tagExprParser) parseOr() (TagExpr, error) {
+	lhs, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek() == "or" {
+		p.pos++
+		rhs, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		lhs = &orExpr{lhs, rhs}
+	}
+
+	return lhs, nil
+}
+
+func (p *tagExprParser) parseAnd() (TagExpr, error) {
+	lhs, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek() == "and" {
+		p.pos++
+		rhs, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		lhs = &andExpr{lhs, rhs}
+	}
+
+	return lhs, nil
+}
+
+func (p *tagExprParser) parseUnary() (TagExpr, error) {
+	if p.peek() == "not" {
+		p.pos++
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &notExpr{operand}, nil
+	}
+
+	return p.parsePrimary()
+}
+
+func (p *tagExprParser) parsePrimary() (TagExpr, error) {
+	token := p.peek()
+	switch {
+	case token == "":
+		return nil, fmt.Errorf("unexpected end of tag expression")
+	case token == "(":
+		p.pos++
+		e, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ")" {
+			return nil, fmt.Errorf("missing ) in tag expression")
+		}
+		p.pos++
+		return e, nil
+	case model.IsTagName(token):
+		p.pos++
+		return &tagRef{token}, nil
+	default:
+		return nil, fmt.Errorf("unexpected %q in tag expression", token)
+	}
+}
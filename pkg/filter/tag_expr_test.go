@@ -0,0 +1,36 @@
+package filter
+
+import (
+	"github.com/autopp/spexec/pkg/model"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ParseTagExpr()", func() {
+	DescribeTable("success cases",
+		func(src string, expected string, tags []string, matched bool) {
+			e, err := ParseTagExpr(src)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(e.String()).To(Equal(expected))
+			Expect(e.Match(&model.Test{Tags: tags})).To(Equal(matched))
+		},
+		Entry("with a tag", "slow", "slow", []string{"slow"}, true),
+		Entry("with not", "not slow", "(not slow)", []string{"slow"}, false),
+		Entry("with and", "slow and network", "(slow and network)", []string{"slow"}, false),
+		Entry("with or", "slow or network", "(slow or network)", []string{"network"}, true),
+		Entry("with and/or, and binds tighter", "a or b and c", "(a or (b and c))", []string{"a"}, true),
+		Entry("with parens", "(a or b) and not c", "((a or b) and (not c))", []string{"b", "c"}, false),
+	)
+
+	DescribeTable("failure cases",
+		func(src string, expectedErr string) {
+			_, err := ParseTagExpr(src)
+			Expect(err).To(MatchError(expectedErr))
+		},
+		Entry("with empty string", " ", "tag expression is empty"),
+		Entry("with missing operand", "slow and", "unexpected end of tag expression"),
+		Entry("with missing )", "(slow or fast", "missing ) in tag expression"),
+		Entry("with extra token", "slow fast", `unexpected "fast" in tag expression`),
+		Entry("with invalid tag", "slow or $", `unexpected "$" in tag expression`),
+	)
+})
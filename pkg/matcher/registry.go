@@ -15,6 +15,8 @@
 package matcher
 
 import (
+	"sort"
+
 	"github.com/autopp/spexec/pkg/errors"
 	"github.com/autopp/spexec/pkg/model"
 )
@@ -23,6 +25,7 @@ type matcherParserEntry[T any] struct {
 	parser       MatcherParser[T]
 	hasDefault   bool
 	defaultParam any
+	paramSchema  model.Map
 }
 
 type MatcherParserRegistry[T any] struct {
@@ -30,33 +33,92 @@ type MatcherParserRegistry[T any] struct {
 	matchers map[string]*matcherParserEntry[T]
 }
 
+// AddOption is an option of Add and AddWithDefault
+type AddOption func(*addConfig)
+
+type addConfig struct {
+	paramSchema model.Map
+}
+
+// WithParamSchema sets the JSON Schema of the matcher parameter.
+// Without it, any parameter is accepted in the schema.
+func WithParamSchema(schema model.Map) AddOption {
+	return func(c *addConfig) {
+		c.paramSchema = schema
+	}
+}
+
 func NewMatcherParserRegistry[T any](target string) *MatcherParserRegistry[T] {
 	return &MatcherParserRegistry[T]{target: target, matchers: make(map[string]*matcherParserEntry[T])}
 }
 
-func (r *MatcherParserRegistry[T]) Add(name string, p MatcherParser[T]) error {
+func (r *MatcherParserRegistry[T]) Add(name string, p MatcherParser[T], opts ...AddOption) error {
+	return r.add(name, &matcherParserEntry[T]{parser: p, hasDefault: false}, opts)
+}
+
+func (r *MatcherParserRegistry[T]) AddWithDefault(name string, p MatcherParser[T], defaultParam any, opts ...AddOption) error {
+	return r.add(name, &matcherParserEntry[T]{parser: p, hasDefault: true, defaultParam: defaultParam}, opts)
+}
+
+func (r *MatcherParserRegistry[T]) add(name string, entry *matcherParserEntry[T], opts []AddOption) error {
 	_, ok := r.matchers[name]
 	if ok {
 		return errors.Errorf(errors.ErrInternalError, "matcher %s is already registered", name)
 	}
-	r.matchers[name] = &matcherParserEntry[T]{
-		parser:     p,
-		hasDefault: false,
+
+	c := &addConfig{paramSchema: model.Map{}}
+	for _, opt := range opts {
+		opt(c)
 	}
+	entry.paramSchema = c.paramSchema
+	r.matchers[name] = entry
 	return nil
 }
 
-func (r *MatcherParserRegistry[T]) AddWithDefault(name string, p MatcherParser[T], defaultParam any) error {
-	_, ok := r.matchers[name]
-	if ok {
-		return errors.Errorf(errors.ErrInternalError, "matcher %s is already registered", name)
+// SchemaName returns the name of the definition returned by Schema
+func (r *MatcherParserRegistry[T]) SchemaName() string {
+	return r.target + "Matcher"
+}
+
+// SchemaRef returns the JSON Schema which refers the definition returned by Schema.
+// It is expected to be used as the parameter schema of matchers which take other matchers.
+func (r *MatcherParserRegistry[T]) SchemaRef() model.Map {
+	return model.Map{"$ref": "#/$defs/" + r.SchemaName()}
+}
+
+// Schema returns the JSON Schema of the matcher specifiers, which should be placed in $defs as SchemaName.
+// Parameters of matchers can be also given as variableSchema (e.g. variable reference).
+func (r *MatcherParserRegistry[T]) Schema(variableSchema model.Map) model.Map {
+	names := make([]string, 0, len(r.matchers))
+	for name := range r.matchers {
+		names = append(names, name)
 	}
-	r.matchers[name] = &matcherParserEntry[T]{
-		parser:       p,
-		hasDefault:   true,
-		defaultParam: defaultParam,
+	sort.Strings(names)
+
+	defaultNames := make([]any, 0)
+	properties := model.Map{}
+	for _, name := range names {
+		entry := r.matchers[name]
+		if entry.hasDefault {
+			defaultNames = append(defaultNames, name)
+		}
+		properties[name] = model.Map{"anyOf": []any{entry.paramSchema, variableSchema}}
 	}
-	return nil
+
+	specifiers := []any{
+		model.Map{
+			"type":                 "object",
+			"properties":           properties,
+			"additionalProperties": false,
+			"minProperties":        1,
+			"maxProperties":        1,
+		},
+	}
+	if len(defaultNames) != 0 {
+		specifiers = append(specifiers, model.Map{"enum": defaultNames})
+	}
+
+	return model.Map{"anyOf": specifiers}
 }
 
 func (r *MatcherParserRegistry[T]) get(v *model.Validator, x any) (string, MatcherParser[T], any) {
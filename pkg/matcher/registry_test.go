@@ -212,6 +212,32 @@ var _ = Describe("MatcherRegistry", func() {
 			})
 		})
 	})
+
+	Describe("Schema()", func() {
+		It("returns the schema of matcher specifiers with the parameter schemas", func() {
+			variable := model.Map{"$ref": "#/$defs/variable"}
+			r.Add(name, parseExampleMatcher, matcher.WithParamSchema(model.Map{"type": "integer"}))
+			r.AddWithDefault(withDefaultName, parseExampleMatcher, true)
+
+			Expect(r.SchemaName()).To(Equal("intMatcher"))
+			Expect(r.SchemaRef()).To(Equal(model.Map{"$ref": "#/$defs/intMatcher"}))
+			Expect(r.Schema(variable)).To(Equal(model.Map{
+				"anyOf": []any{
+					model.Map{
+						"type": "object",
+						"properties": model.Map{
+							name:            model.Map{"anyOf": []any{model.Map{"type": "integer"}, variable}},
+							withDefaultName: model.Map{"anyOf": []any{model.Map{}, variable}},
+						},
+						"additionalProperties": false,
+						"minProperties":        1,
+						"maxProperties":        1,
+					},
+					model.Map{"enum": []any{withDefaultName}},
+				},
+			}))
+		})
+	})
 })
 
 var _ = Describe("NewStatusMatcherRegistry()", func() {
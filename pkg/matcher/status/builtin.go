@@ -14,11 +14,14 @@
 
 package status
 
-import "github.com/autopp/spexec/pkg/matcher"
+import (
+	"github.com/autopp/spexec/pkg/matcher"
+	"github.com/autopp/spexec/pkg/model"
+)
 
 func NewStatusMatcherRegistryWithBuiltins() *matcher.StatusMatcherRegistry {
 	r := matcher.NewStatusMatcherRegistry()
-	r.Add("eq", ParseEqMatcher)
-	r.AddWithDefault("success", ParseSuccessMatcher, true)
+	r.Add("eq", ParseEqMatcher, matcher.WithParamSchema(model.Map{"type": "integer", "minimum": 0}))
+	r.AddWithDefault("success", ParseSuccessMatcher, true, matcher.WithParamSchema(model.Map{"type": "boolean"}))
 	return r
 }
@@ -14,17 +14,35 @@
 
 package stream
 
-import "github.com/autopp/spexec/pkg/matcher"
+import (
+	"github.com/autopp/spexec/pkg/matcher"
+	"github.com/autopp/spexec/pkg/model"
+)
 
 func NewStreamMatcherRegistryWithBuiltins() *matcher.StreamMatcherRegistry {
 	r := matcher.NewStreamMatcherRegistry()
-	r.Add("eq", ParseEqMatcher)
-	r.Add("beEmpty", ParseBeEmptyMatcher)
-	r.Add("eqJSON", ParseEqJSONMatcher)
-	r.Add("contain", ParseContainMatcher)
-	r.Add("not", ParseNotMatcher)
-	r.Add("any", ParseAnyMatcher)
-	r.Add("satisfy", ParseSatisfyMatcher)
-	r.Add("matchRegexp", ParseMatchRegexpMatcher)
+	r.Add("eq", ParseEqMatcher, matcher.WithParamSchema(model.Map{"type": "string"}))
+	r.Add("beEmpty", ParseBeEmptyMatcher, matcher.WithParamSchema(model.Map{"type": "boolean"}))
+	r.Add("eqJSON", ParseEqJSONMatcher, matcher.WithParamSchema(model.Map{}))
+	r.Add("contain", ParseContainMatcher, matcher.WithParamSchema(model.Map{"type": "string"}))
+	r.Add("not", ParseNotMatcher, matcher.WithParamSchema(r.SchemaRef()))
+	r.Add("any", ParseAnyMatcher, matcher.WithParamSchema(model.Map{"type": "array", "items": r.SchemaRef()}))
+	r.Add("satisfy", ParseSatisfyMatcher, matcher.WithParamSchema(model.Map{
+		"type": "object",
+		"properties": model.Map{
+			"command": model.Map{"type": "array", "items": model.Map{"type": []any{"string", "object"}}},
+			"env": model.Map{
+				"type": "array",
+				"items": model.Map{
+					"type":       "object",
+					"properties": model.Map{"name": model.Map{"type": "string"}, "value": model.Map{"type": "string"}},
+					"required":   []any{"name", "value"},
+				},
+			},
+			"timeout": model.Map{"type": []any{"integer", "string"}},
+		},
+		"required": []any{"command"},
+	}))
+	r.Add("matchRegexp", ParseMatchRegexpMatcher, matcher.WithParamSchema(model.Map{"type": "string", "format": "regex"}))
 	return r
 }
@@ -0,0 +1,80 @@
+// Copyright (C) 2021-2023	 Akira Tanimura (@autopp)
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+)
+
+// Condition decides whether a test should be run
+type Condition interface {
+	// Check returns whether the condition holds with the description of the result
+	Check() (bool, string, error)
+}
+
+// EnvCondition holds when the environment variable is set
+type EnvCondition struct {
+	Name string
+}
+
+func (c *EnvCondition) Check() (bool, string, error) {
+	if _, ok := os.LookupEnv(c.Name); ok {
+		return true, fmt.Sprintf("env $%s is set", c.Name), nil
+	}
+
+	return false, fmt.Sprintf("env $%s is not set", c.Name), nil
+}
+
+// CommandCondition holds when the command is found in PATH
+type CommandCondition struct {
+	Name string
+}
+
+func (c *CommandCondition) Check() (bool, string, error) {
+	if _, err := exec.LookPath(c.Name); err == nil {
+		return true, fmt.Sprintf("command %s is found", c.Name), nil
+	}
+
+	return false, fmt.Sprintf("command %s is not found", c.Name), nil
+}
+
+// ProbeCondition holds when the probe command succeeds
+type ProbeCondition struct {
+	Probe *Hook
+}
+
+func (c *ProbeCondition) Check() (bool, string, error) {
+	message, err := c.Probe.Run()
+	if err != nil {
+		return false, "", err
+	}
+
+	if len(message) == 0 {
+		return true, fmt.Sprintf("probe `%s` succeeded", c.Probe.String()), nil
+	}
+
+	return false, "probe " + message, nil
+}
+
+// ExprCondition is a condition given by a template expression, which is already evaluated on template expansion
+type ExprCondition struct {
+	Value bool
+}
+
+func (c *ExprCondition) Check() (bool, string, error) {
+	return c.Value, fmt.Sprintf("expr is %t", c.Value), nil
+}
@@ -0,0 +1,40 @@
+package model_test
+
+import (
+	"os"
+
+	"github.com/autopp/spexec/pkg/model"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Condition", func() {
+	BeforeEach(func() {
+		os.Setenv("SPEXEC_CONDITION_TEST", "")
+		DeferCleanup(os.Unsetenv, "SPEXEC_CONDITION_TEST")
+	})
+
+	DescribeTable("Check()",
+		func(c model.Condition, expectedHolds bool, expectedDescription string) {
+			holds, description, err := c.Check()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(holds).To(Equal(expectedHolds))
+			Expect(description).To(Equal(expectedDescription))
+		},
+		Entry("EnvCondition with set env", &model.EnvCondition{Name: "SPEXEC_CONDITION_TEST"}, true, "env $SPEXEC_CONDITION_TEST is set"),
+		Entry("EnvCondition with unset env", &model.EnvCondition{Name: "SPEXEC_UNDEFINED"}, false, "env $SPEXEC_UNDEFINED is not set"),
+		Entry("CommandCondition with existing command", &model.CommandCondition{Name: "sh"}, true, "command sh is found"),
+		Entry("CommandCondition with missing command", &model.CommandCondition{Name: "spexec-undefined-command"}, false, "command spexec-undefined-command is not found"),
+		Entry("ProbeCondition with succeeded probe",
+			&model.ProbeCondition{Probe: &model.Hook{Command: []model.StringExpr{model.NewLiteralStringExpr("true")}}},
+			true, "probe `true` succeeded",
+		),
+		Entry("ProbeCondition with failed probe",
+			&model.ProbeCondition{Probe: &model.Hook{Command: []model.StringExpr{model.NewLiteralStringExpr("false")}}},
+			false, "probe `false` failed: process exited with status 1",
+		),
+		Entry("ExprCondition with true", &model.ExprCondition{Value: true}, true, "expr is true"),
+		Entry("ExprCondition with false", &model.ExprCondition{Value: false}, false, "expr is false"),
+	)
+})
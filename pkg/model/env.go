@@ -14,13 +14,16 @@
 
 package model
 
+import "sort"
+
 type Env struct {
 	vars map[string]any
+	used map[string]bool
 	prev *Env
 }
 
 func NewEnv(prev *Env) *Env {
-	env := &Env{vars: make(map[string]any), prev: prev}
+	env := &Env{vars: make(map[string]any), used: make(map[string]bool), prev: prev}
 	return env
 }
 
@@ -41,12 +44,27 @@ func (e *Env) Lookup(name string) (any, bool) {
 	}
 
 	if v, ok := e.vars[name]; ok {
+		e.used[name] = true
 		return v, true
 	}
 
 	return e.prev.Lookup(name)
 }
 
+// IsDefinedInRoot returns whether name is defined in the outermost frame, which holds the variables given from command line
+func (e *Env) IsDefinedInRoot(name string) bool {
+	if e == nil {
+		return false
+	}
+
+	if e.prev == nil {
+		_, ok := e.vars[name]
+		return ok
+	}
+
+	return e.prev.IsDefinedInRoot(name)
+}
+
 func (e *Env) GetCurrentScope() map[string]any {
 	m := map[string]any{}
 	var collectScope func(e *Env)
@@ -60,5 +78,19 @@ func (e *Env) GetCurrentScope() map[string]any {
 		}
 	}
 	collectScope(e)
+
 	return m
 }
+
+// GetUnusedNames returns the sorted names of the variables defined in the current frame and never looked up
+func (e *Env) GetUnusedNames() []string {
+	names := make([]string, 0)
+	for name := range e.vars {
+		if !e.used[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	return names
+}
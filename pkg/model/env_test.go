@@ -60,6 +60,18 @@ var _ = Describe("Env", func() {
 		})
 	})
 
+	Describe("IsDefinedInRoot()", func() {
+		It("returns whether the name is defined in the outermost frame", func() {
+			env.Define("root", "spexec")
+			env = NewEnv(env)
+			env.Define("inner", "spexec")
+
+			Expect(env.IsDefinedInRoot("root")).To(BeTrue())
+			Expect(env.IsDefinedInRoot("inner")).To(BeFalse())
+			Expect(env.IsDefinedInRoot("undefined")).To(BeFalse())
+		})
+	})
+
 	Describe("GetCurrentScope()", func() {
 		It("returns map as current scope", func() {
 			env.Define("command", "spexec")
@@ -76,4 +88,25 @@ var _ = Describe("Env", func() {
 			Expect(env.GetCurrentScope()).To(Equal(expected))
 		})
 	})
+
+	Describe("GetUnusedNames()", func() {
+		It("returns names in the current frame which are not looked up", func() {
+			env.Define("outer", "spexec")
+			env = NewEnv(env)
+			env.Define("command", "spexec")
+			env.Define("args", "spec.yaml")
+			env.Define("flags", "--strict")
+			env.Lookup("args")
+			env.Lookup("outer")
+
+			Expect(env.GetUnusedNames()).To(Equal([]string{"command", "flags"}))
+		})
+
+		It("does not treat variables in GetCurrentScope() as used", func() {
+			env.Define("command", "spexec")
+			env.GetCurrentScope()
+
+			Expect(env.GetUnusedNames()).To(Equal([]string{"command"}))
+		})
+	})
 })
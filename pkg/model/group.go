@@ -0,0 +1,87 @@
+// Copyright (C) 2021-2023	 Akira Tanimura (@autopp)
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import "math/rand"
+
+// Group is a named set of tests which may be nested in another group
+type Group struct {
+	Name       string
+	Parent     *Group
+	BeforeAll  []*Hook
+	BeforeEach []*Hook
+	AfterEach  []*Hook
+	AfterAll   []*Hook
+}
+
+// GetPath returns groups from the outermost one to g
+func (g *Group) GetPath() []*Group {
+	if g == nil {
+		return nil
+	}
+
+	return append(g.Parent.GetPath(), g)
+}
+
+// GetNames returns non-empty names of groups from the outermost one to g
+func (g *Group) GetNames() []string {
+	var names []string
+	for _, group := range g.GetPath() {
+		if len(group.Name) != 0 {
+			names = append(names, group.Name)
+		}
+	}
+
+	return names
+}
+
+// ShuffleTests returns tests in random order by r.
+// Tests in the same group are kept contiguous, so hooks of groups are run once as in the original order.
+func ShuffleTests(tests []*Test, r *rand.Rand) []*Test {
+	return shuffleTests(tests, 0, r)
+}
+
+// shuffleTests shuffles tests whose groups have the same outer groups to the given depth
+func shuffleTests(tests []*Test, depth int, r *rand.Rand) []*Test {
+	// a unit is a test directly in the group at depth or the tests in the same subgroup
+	var units [][]*Test
+	var current *Group
+	for _, t := range tests {
+		path := t.Group.GetPath()
+		if len(path) <= depth+1 {
+			units = append(units, []*Test{t})
+			current = nil
+		} else if path[depth+1] == current {
+			units[len(units)-1] = append(units[len(units)-1], t)
+		} else {
+			units = append(units, []*Test{t})
+			current = path[depth+1]
+		}
+	}
+
+	r.Shuffle(len(units), func(i, j int) {
+		units[i], units[j] = units[j], units[i]
+	})
+
+	shuffled := make([]*Test, 0, len(tests))
+	for _, unit := range units {
+		if len(unit[0].Group.GetPath()) > depth+1 {
+			unit = shuffleTests(unit, depth+1, r)
+		}
+		shuffled = append(shuffled, unit...)
+	}
+
+	return shuffled
+}
@@ -0,0 +1,79 @@
+package model
+
+import (
+	"math/rand"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Group", func() {
+	var root, outer, inner *Group
+
+	JustBeforeEach(func() {
+		root = &Group{}
+		outer = &Group{Name: "outer", Parent: root}
+		inner = &Group{Name: "inner", Parent: outer}
+	})
+
+	Describe("GetPath()", func() {
+		It("returns groups from the outermost one", func() {
+			Expect(inner.GetPath()).To(Equal([]*Group{root, outer, inner}))
+		})
+
+		It("returns nil for nil", func() {
+			var g *Group
+			Expect(g.GetPath()).To(BeNil())
+		})
+	})
+
+	Describe("GetNames()", func() {
+		It("returns names of groups except empty ones", func() {
+			Expect(inner.GetNames()).To(Equal([]string{"outer", "inner"}))
+		})
+	})
+
+	Describe("ShuffleTests()", func() {
+		It("shuffles tests keeping tests in the same group contiguous", func() {
+			other := &Group{Name: "other", Parent: root}
+			tests := []*Test{
+				{Name: "root1", Group: root},
+				{Name: "root2", Group: root},
+				{Name: "outer1", Group: outer},
+				{Name: "inner1", Group: inner},
+				{Name: "inner2", Group: inner},
+				{Name: "outer2", Group: outer},
+				{Name: "other1", Group: other},
+				{Name: "other2", Group: other},
+			}
+
+			for seed := int64(0); seed < 20; seed++ {
+				shuffled := ShuffleTests(tests, rand.New(rand.NewSource(seed)))
+				Expect(shuffled).To(ConsistOf(tests))
+
+				left := map[*Group]bool{}
+				var entered []*Group
+				for _, t := range shuffled {
+					path := t.Group.GetPath()
+					n := 0
+					for n < len(entered) && n < len(path) && entered[n] == path[n] {
+						n++
+					}
+					for _, g := range entered[n:] {
+						left[g] = true
+					}
+					for _, g := range path[n:] {
+						Expect(left[g]).To(BeFalse(), "group %q is entered twice with seed %d", g.Name, seed)
+					}
+					entered = path
+				}
+			}
+		})
+
+		It("returns the same order with the same seed", func() {
+			tests := []*Test{{Name: "test1", Group: root}, {Name: "test2", Group: root}, {Name: "test3", Group: outer}, {Name: "test4", Group: inner}}
+
+			Expect(ShuffleTests(tests, rand.New(rand.NewSource(42)))).To(Equal(ShuffleTests(tests, rand.New(rand.NewSource(42)))))
+		})
+	})
+})
@@ -0,0 +1,77 @@
+// Copyright (C) 2021-2023	 Akira Tanimura (@autopp)
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Wing924/shellwords"
+	"github.com/autopp/spexec/pkg/exec"
+	"github.com/autopp/spexec/pkg/util"
+)
+
+// Hook is a command executed around tests of a group
+type Hook struct {
+	Command []StringExpr
+	Dir     string
+	Env     []util.StringVar
+	Timeout time.Duration
+}
+
+func (h *Hook) String() string {
+	command := make([]string, len(h.Command))
+	for i, x := range h.Command {
+		command[i] = x.String()
+	}
+
+	return shellwords.Join(command)
+}
+
+// Run executes the hook and returns the failure message when the command did not succeed
+func (h *Hook) Run() (string, error) {
+	command, cleanup, err, _ := EvalStringExprs(h.Command)
+	defer cleanup()
+	if err != nil {
+		return "", err
+	}
+
+	e, err := exec.New(command, h.Dir, nil, h.Env, exec.WithTimeout(h.Timeout))
+	if err != nil {
+		return "", err
+	}
+
+	r := e.Run()
+	var reason string
+	if r.Err != nil {
+		reason = r.Err.Error()
+	} else if r.IsTimeout {
+		reason = "process was timeout"
+	} else if r.Signal != nil {
+		reason = fmt.Sprintf("process was signaled (%s)", r.Signal.String())
+	} else if r.Status != 0 {
+		reason = fmt.Sprintf("process exited with status %d", r.Status)
+	} else {
+		return "", nil
+	}
+
+	message := fmt.Sprintf("`%s` failed: %s", h.String(), reason)
+	if stderr := strings.TrimSpace(string(r.Stderr)); len(stderr) != 0 {
+		message += "\n" + stderr
+	}
+
+	return message, nil
+}
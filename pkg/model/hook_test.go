@@ -0,0 +1,45 @@
+package model_test
+
+import (
+	"time"
+
+	"github.com/autopp/spexec/pkg/model"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Hook", func() {
+	Describe("Run()", func() {
+		DescribeTable("succeeded cases",
+			func(hook *model.Hook, expectedMessage string) {
+				message, err := hook.Run()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(message).To(Equal(expectedMessage))
+			},
+			Entry("command is succeeded", &model.Hook{
+				Command: []model.StringExpr{model.NewLiteralStringExpr("true")},
+			}, ""),
+			Entry("command is failed", &model.Hook{
+				Command: []model.StringExpr{model.NewLiteralStringExpr("false")},
+			}, "`false` failed: process exited with status 1"),
+			Entry("command is failed with stderr", &model.Hook{
+				Command: []model.StringExpr{model.NewLiteralStringExpr("bash"), model.NewLiteralStringExpr("-c"), model.NewLiteralStringExpr("echo oops >&2; exit 2")},
+			}, "`bash -c echo\\ oops\\ \\>\\&2\\;\\ exit\\ 2` failed: process exited with status 2\noops"),
+			Entry("process is timeout", &model.Hook{
+				Command: []model.StringExpr{model.NewLiteralStringExpr("sleep"), model.NewLiteralStringExpr("1")},
+				Timeout: 1 * time.Millisecond,
+			}, "`sleep 1` failed: process was timeout"),
+		)
+
+		DescribeTable("failed cases",
+			func(hook *model.Hook, expectedErr string) {
+				_, err := hook.Run()
+				Expect(err).To(MatchError(expectedErr))
+			},
+			Entry("command evaluating is failed", &model.Hook{
+				Command: []model.StringExpr{model.NewEnvStringExpr("undefined")},
+			}, "environment variable $undefined is not defined"),
+		)
+	})
+})
@@ -18,6 +18,7 @@ import (
 	"bytes"
 	"encoding/gob"
 	"text/template"
+	"text/template/parse"
 
 	"github.com/autopp/spexec/pkg/errors"
 )
@@ -60,14 +61,87 @@ func (tt *TemplateText) Expand(env *Env, v *Validator, value any) (any, bool, er
 		return nil, false, err
 	}
 
+	scope := env.GetCurrentScope()
+	for _, t := range t.Templates() {
+		if t.Tree != nil {
+			markReferredVars(env, scope, t.Tree.Root)
+		}
+	}
+
 	buf := new(bytes.Buffer)
-	if err = t.Execute(buf, Map{"Var": env.GetCurrentScope()}); err != nil {
+	if err = t.Execute(buf, Map{"Var": scope}); err != nil {
 		return nil, false, err
 	}
 
 	return buf.String(), true, nil
 }
 
+// markReferredVars marks variables referred as `.Var.name` in node as used.
+// When `.Var` or `.` is referred as a whole (e.g. `index .Var "name"`), all variables in scope are marked.
+func markReferredVars(env *Env, scope map[string]any, node parse.Node) {
+	markAll := func() {
+		for name := range scope {
+			env.Lookup(name)
+		}
+	}
+
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, c := range n.Nodes {
+			markReferredVars(env, scope, c)
+		}
+	case *parse.ActionNode:
+		markReferredVars(env, scope, n.Pipe)
+	case *parse.IfNode:
+		markReferredVars(env, scope, &n.BranchNode)
+	case *parse.RangeNode:
+		markReferredVars(env, scope, &n.BranchNode)
+	case *parse.WithNode:
+		markReferredVars(env, scope, &n.BranchNode)
+	case *parse.BranchNode:
+		markReferredVars(env, scope, n.Pipe)
+		markReferredVars(env, scope, n.List)
+		markReferredVars(env, scope, n.ElseList)
+	case *parse.TemplateNode:
+		markReferredVars(env, scope, n.Pipe)
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+		for _, c := range n.Cmds {
+			markReferredVars(env, scope, c)
+		}
+	case *parse.CommandNode:
+		for _, a := range n.Args {
+			markReferredVars(env, scope, a)
+		}
+	case *parse.ChainNode:
+		markReferredVars(env, scope, n.Node)
+	case *parse.FieldNode:
+		if n.Ident[0] == "Var" {
+			if len(n.Ident) > 1 {
+				env.Lookup(n.Ident[1])
+			} else {
+				markAll()
+			}
+		}
+	case *parse.DotNode:
+		markAll()
+	case *parse.VariableNode:
+		// `$` is bound to the whole data
+		if n.Ident[0] == "$" && (len(n.Ident) == 1 || n.Ident[1] == "Var") {
+			if len(n.Ident) > 2 {
+				env.Lookup(n.Ident[2])
+			} else {
+				markAll()
+			}
+		}
+	}
+}
+
 type TemplateFieldRef struct {
 	field string
 	next  TemplateRef
@@ -212,6 +286,11 @@ func NewTemplatableFromText[T any](text string) *Templatable[T] {
 	return NewTemplatableFromTemplateValue[T](NewTemplateValue(Map{"$t": text}, []TemplateRef{NewTemplateText(text)}))
 }
 
+// IsTemplate reports whether the value is given as a template (e.g. variable or template text)
+func (t *Templatable[T]) IsTemplate() bool {
+	return t.tv != nil
+}
+
 func (t *Templatable[T]) Expand(env *Env, v *Validator) (T, error) {
 	if t.tv == nil {
 		return t.value, nil
@@ -0,0 +1,98 @@
+// Copyright (C) 2021-2023	 Akira Tanimura (@autopp)
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package template
+
+import (
+	"github.com/autopp/spexec/pkg/errors"
+	"github.com/autopp/spexec/pkg/model"
+)
+
+// ConditionTemplate is a template of model.Condition. Only one of the fields is set.
+type ConditionTemplate struct {
+	Env     string
+	Command string
+	Probe   *HookTemplate
+	Expr    *model.Templatable[any]
+}
+
+func (ct *ConditionTemplate) Expand(env *model.Env, v *model.Validator) (model.Condition, error) {
+	switch {
+	case len(ct.Env) != 0:
+		return &model.EnvCondition{Name: ct.Env}, nil
+	case len(ct.Command) != 0:
+		return &model.CommandCondition{Name: ct.Command}, nil
+	case ct.Probe != nil:
+		var probe *model.Hook
+		var err error
+		v.InField("probe", func() {
+			probe, err = ct.Probe.Expand(env, v)
+		})
+		if err != nil {
+			return nil, err
+		}
+		return &model.ProbeCondition{Probe: probe}, nil
+	case ct.Expr != nil:
+		var value any
+		var err error
+		var c *model.ExprCondition
+		v.InField("expr", func() {
+			value, err = ct.Expr.Expand(env, v)
+			if err != nil {
+				return
+			}
+			switch value {
+			case true, "true":
+				c = &model.ExprCondition{Value: true}
+			case false, "false":
+				c = &model.ExprCondition{Value: false}
+			default:
+				v.AddViolation(`should be bool or "true" or "false", but is %s`, model.TypeNameOf(value))
+				c = &model.ExprCondition{Value: false}
+			}
+		})
+		if err != nil {
+			return nil, err
+		}
+		return c, nil
+	default:
+		return nil, errors.New(errors.ErrInternalError, "condition is empty")
+	}
+}
+
+func expandConditions(env *model.Env, v *model.Validator, field string, cts []*ConditionTemplate) ([]model.Condition, error) {
+	if len(cts) == 0 {
+		return nil, nil
+	}
+
+	var err error
+	conditions := make([]model.Condition, 0, len(cts))
+	v.InField(field, func() {
+		for i, ct := range cts {
+			v.InIndex(i, func() {
+				var c model.Condition
+				c, err = ct.Expand(env, v)
+				conditions = append(conditions, c)
+			})
+			if err != nil {
+				return
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return conditions, nil
+}
@@ -0,0 +1,46 @@
+package template
+
+import (
+	"github.com/autopp/spexec/pkg/model"
+	"github.com/autopp/spexec/pkg/util"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ConditionTemplate", func() {
+	Describe("Expand()", func() {
+		var env *model.Env
+		var v *model.Validator
+
+		JustBeforeEach(func() {
+			env = model.NewEnv(nil)
+			env.Define("enabled", "true")
+			env.Define("answer", 42)
+			v, _ = model.NewValidator("", true)
+		})
+
+		DescribeTable("success cases",
+			func(ct *ConditionTemplate, expected model.Condition) {
+				c, err := ct.Expand(env, v)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(v.Error()).NotTo(HaveOccurred())
+				Expect(c).To(Equal(expected))
+			},
+			Entry("with env", &ConditionTemplate{Env: "CI"}, &model.EnvCondition{Name: "CI"}),
+			Entry("with command", &ConditionTemplate{Command: "docker"}, &model.CommandCondition{Name: "docker"}),
+			Entry("with probe",
+				&ConditionTemplate{Probe: &HookTemplate{Command: []*model.Templatable[any]{model.NewTemplatableFromValue[any]("true")}, Dir: "/tmp"}},
+				&model.ProbeCondition{Probe: &model.Hook{Command: []model.StringExpr{model.NewLiteralStringExpr("true")}, Dir: "/tmp", Env: []util.StringVar{}}},
+			),
+			Entry("with bool expr", &ConditionTemplate{Expr: model.NewTemplatableFromValue[any](false)}, &model.ExprCondition{Value: false}),
+			Entry("with string expr from variable", &ConditionTemplate{Expr: model.NewTemplatableFromVariable[any]("enabled")}, &model.ExprCondition{Value: true}),
+		)
+
+		It("adds violation when the expr is neither bool nor boolean string", func() {
+			ct := &ConditionTemplate{Expr: model.NewTemplatableFromVariable[any]("answer")}
+			_, err := ct.Expand(env, v)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(v.Error()).To(MatchError(`$.expr: should be bool or "true" or "false", but is int`))
+		})
+	})
+})
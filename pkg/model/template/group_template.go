@@ -0,0 +1,58 @@
+// Copyright (C) 2021-2023	 Akira Tanimura (@autopp)
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package template
+
+import (
+	"github.com/autopp/spexec/pkg/matcher"
+	"github.com/autopp/spexec/pkg/model"
+)
+
+type GroupTemplate struct {
+	Name   *model.Templatable[string]
+	Vars   []*TemplatableVar
+	Hooks  HooksTemplate
+	Tests  []*TestTemplate
+	Groups []*GroupTemplate
+}
+
+// Expand expands the tests in the group and its descendant groups.
+// Variables of the group shadow the same name variables of the spec and outer groups, but not ones given from command line.
+func (gt *GroupTemplate) Expand(env *model.Env, v *model.Validator, parent *model.Group, statusMR *matcher.StatusMatcherRegistry, streamMR *matcher.StreamMatcherRegistry) ([]*model.Test, error) {
+	groupEnv, err := defineVars(env, v, gt.Vars)
+	if err != nil {
+		return nil, err
+	}
+
+	var name string
+	v.InField("name", func() {
+		name, err = gt.Name.Expand(groupEnv, v)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	group := &model.Group{Name: name, Parent: parent}
+	if err := gt.Hooks.expandInto(groupEnv, v, group); err != nil {
+		return nil, err
+	}
+
+	tests, err := expandChildren(groupEnv, v, group, gt.Tests, gt.Groups, statusMR, streamMR)
+	if err != nil {
+		return nil, err
+	}
+	warnUnusedVars(groupEnv, v)
+
+	return tests, nil
+}
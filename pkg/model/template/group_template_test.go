@@ -0,0 +1,90 @@
+package template
+
+import (
+	"github.com/autopp/spexec/pkg/matcher"
+	"github.com/autopp/spexec/pkg/model"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GroupTemplate", func() {
+	Describe("Expand()", func() {
+		var env *model.Env
+		var v *model.Validator
+		var statusMR *matcher.StatusMatcherRegistry
+		var streamMR *matcher.StreamMatcherRegistry
+
+		JustBeforeEach(func() {
+			env = model.NewEnv(nil)
+			env.Define("greeting", "hello")
+			v, _ = model.NewValidator("", true)
+			statusMR = matcher.NewStatusMatcherRegistry()
+			streamMR = matcher.NewStreamMatcherRegistry()
+		})
+
+		It("expands tests of the group and its descendant groups", func() {
+			parent := &model.Group{Name: "parent"}
+			gt := &GroupTemplate{
+				Name: model.NewTemplatableFromText[string]("says {{.Var.message}}"),
+				Vars: []*TemplatableVar{{Name: "message", Value: model.NewTemplatableFromValue[any]("hi")}},
+				Tests: []*TestTemplate{
+					{Command: []*model.Templatable[any]{model.NewTemplatableFromVariable[any]("message")}},
+				},
+				Groups: []*GroupTemplate{
+					{
+						Name: model.NewTemplatableFromValue("nested"),
+						Tests: []*TestTemplate{
+							{Command: []*model.Templatable[any]{model.NewTemplatableFromValue[any]("echo")}},
+						},
+					},
+				},
+			}
+
+			tests, err := gt.Expand(env, v, parent, statusMR, streamMR)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(tests).To(HaveLen(2))
+
+			Expect(tests[0].Command).To(Equal([]model.StringExpr{model.NewLiteralStringExpr("hi")}))
+			Expect(tests[0].Group.Name).To(Equal("says hi"))
+			Expect(tests[0].Group.Parent).To(BeIdenticalTo(parent))
+
+			Expect(tests[1].Group.Name).To(Equal("nested"))
+			Expect(tests[1].Group.Parent).To(BeIdenticalTo(tests[0].Group))
+			Expect(tests[1].GetName()).To(Equal("parent says hi nested echo"))
+		})
+
+		It("shadows the variables of outer scope but not ones given from command line", func() {
+			specEnv := model.NewEnv(env)
+			specEnv.Define("message", "hi")
+			gt := &GroupTemplate{
+				Name: model.NewTemplatableFromValue("group"),
+				Vars: []*TemplatableVar{
+					{Name: "greeting", Value: model.NewTemplatableFromValue[any]("bye")},
+					{Name: "message", Value: model.NewTemplatableFromValue[any]("hey")},
+				},
+				Tests: []*TestTemplate{
+					{
+						Command: []*model.Templatable[any]{
+							model.NewTemplatableFromVariable[any]("greeting"),
+							model.NewTemplatableFromVariable[any]("message"),
+						},
+					},
+				},
+			}
+
+			tests, err := gt.Expand(specEnv, v, nil, statusMR, streamMR)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(tests[0].Command).To(Equal([]model.StringExpr{model.NewLiteralStringExpr("hello"), model.NewLiteralStringExpr("hey")}))
+		})
+
+		It("returns error with the path of the group", func() {
+			gt := &GroupTemplate{
+				Name:  model.NewTemplatableFromVariable[string]("undefined"),
+				Tests: []*TestTemplate{},
+			}
+
+			_, err := gt.Expand(env, v, nil, statusMR, streamMR)
+			Expect(err).To(MatchError("$.name.$undefined: is not defined"))
+		})
+	})
+})
@@ -0,0 +1,107 @@
+// Copyright (C) 2021-2023	 Akira Tanimura (@autopp)
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package template
+
+import (
+	"time"
+
+	"github.com/autopp/spexec/pkg/model"
+	"github.com/autopp/spexec/pkg/util"
+)
+
+type HookTemplate struct {
+	Command []*model.Templatable[any]
+	Dir     string
+	Env     []*TemplatableStringVar
+	Timeout time.Duration
+}
+
+func (ht *HookTemplate) Expand(env *model.Env, v *model.Validator) (*model.Hook, error) {
+	var err error
+	command := make([]model.StringExpr, 0, len(ht.Command))
+	v.InField("command", func() {
+		for i, ct := range ht.Command {
+			v.InIndex(i, func() {
+				var x any
+				x, err = ct.Expand(env, v)
+				if err != nil {
+					return
+				}
+
+				if c, ok := v.MustBeStringExpr(x); ok {
+					command = append(command, c)
+				}
+			})
+			if err != nil {
+				return
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	hEnv := make([]util.StringVar, 0, len(ht.Env))
+	for _, tsv := range ht.Env {
+		value, err := tsv.Value.Expand(env, v)
+		if err != nil {
+			return nil, err
+		}
+		hEnv = append(hEnv, util.StringVar{Name: tsv.Name, Value: value})
+	}
+
+	return &model.Hook{Command: command, Dir: ht.Dir, Env: hEnv, Timeout: ht.Timeout}, nil
+}
+
+// HooksTemplate holds hooks of a spec or a group
+type HooksTemplate struct {
+	BeforeAll  []*HookTemplate
+	BeforeEach []*HookTemplate
+	AfterEach  []*HookTemplate
+	AfterAll   []*HookTemplate
+}
+
+func (hst *HooksTemplate) expandInto(env *model.Env, v *model.Validator, group *model.Group) error {
+	var err error
+	expandHooks := func(field string, hts []*HookTemplate) []*model.Hook {
+		hooks := make([]*model.Hook, 0, len(hts))
+		v.InField(field, func() {
+			for i, ht := range hts {
+				var h *model.Hook
+				v.InIndex(i, func() {
+					h, err = ht.Expand(env, v)
+				})
+				if err != nil {
+					return
+				}
+				hooks = append(hooks, h)
+			}
+		})
+		return hooks
+	}
+
+	if group.BeforeAll = expandHooks("beforeAll", hst.BeforeAll); err != nil {
+		return err
+	}
+	if group.BeforeEach = expandHooks("beforeEach", hst.BeforeEach); err != nil {
+		return err
+	}
+	if group.AfterEach = expandHooks("afterEach", hst.AfterEach); err != nil {
+		return err
+	}
+	group.AfterAll = expandHooks("afterAll", hst.AfterAll)
+
+	return err
+}
@@ -0,0 +1,145 @@
+// Copyright (C) 2021-2023	 Akira Tanimura (@autopp)
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package template
+
+import (
+	"github.com/autopp/spexec/pkg/matcher"
+	"github.com/autopp/spexec/pkg/model"
+)
+
+type TemplatableVar struct {
+	Name  string
+	Value *model.Templatable[any]
+}
+
+type SpecTemplate struct {
+	Vars   []*TemplatableVar
+	Hooks  HooksTemplate
+	Tests  []*TestTemplate
+	Groups []*GroupTemplate
+}
+
+// Expand defines the spec variables in a new scope of env and expands all tests in it.
+// Variables already defined in env (e.g. given from command line) take precedence over the spec variables.
+func (st *SpecTemplate) Expand(env *model.Env, v *model.Validator, statusMR *matcher.StatusMatcherRegistry, streamMR *matcher.StreamMatcherRegistry) ([]*model.Test, error) {
+	specEnv, err := defineVars(env, v, st.Vars)
+	if err != nil {
+		return nil, err
+	}
+
+	root := &model.Group{}
+	if err := st.Hooks.expandInto(specEnv, v, root); err != nil {
+		return nil, err
+	}
+
+	tests, err := expandChildren(specEnv, v, root, st.Tests, st.Groups, statusMR, streamMR)
+	if err != nil {
+		return nil, err
+	}
+	warnUnusedVars(specEnv, v)
+
+	return tests, nil
+}
+
+// expandChildren expands tts and then gts, so tests precede groups regardless of their order in the spec file
+func expandChildren(env *model.Env, v *model.Validator, group *model.Group, tts []*TestTemplate, gts []*GroupTemplate, statusMR *matcher.StatusMatcherRegistry, streamMR *matcher.StreamMatcherRegistry) ([]*model.Test, error) {
+	var err error
+	tests := make([]*model.Test, 0, len(tts))
+	names := map[string]bool{}
+	v.InField("tests", func() {
+		for i, tt := range tts {
+			var expanded []*model.Test
+			v.InIndex(i, func() {
+				expanded, err = tt.ExpandAll(env, v, statusMR, streamMR)
+				if err != nil {
+					return
+				}
+				for _, t := range expanded {
+					t.Group = group
+					if name := t.GetName(); names[name] {
+						v.AddWarning("name %q is duplicated", name)
+					} else {
+						names[name] = true
+					}
+				}
+			})
+			if err != nil {
+				return
+			}
+			tests = append(tests, expanded...)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	v.InField("groups", func() {
+		for i, gt := range gts {
+			var groupTests []*model.Test
+			v.InIndex(i, func() {
+				groupTests, err = gt.Expand(env, v, group, statusMR, streamMR)
+			})
+			if err != nil {
+				return
+			}
+			tests = append(tests, groupTests...)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return tests, nil
+}
+
+// defineVars defines vars in a new frame of env.
+// Variables given from command line (defined in the root frame) are never shadowed, but ones of outer spec or groups are.
+func defineVars(env *model.Env, v *model.Validator, vars []*TemplatableVar) (*model.Env, error) {
+	newEnv := model.NewEnv(env)
+	var err error
+	v.InField("vars", func() {
+		for _, tv := range vars {
+			if env.IsDefinedInRoot(tv.Name) {
+				continue
+			}
+
+			var value any
+			v.InField(tv.Name, func() {
+				value, err = tv.Value.Expand(env, v)
+			})
+			if err != nil {
+				return
+			}
+			newEnv.Define(tv.Name, value)
+		}
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return newEnv, nil
+}
+
+// warnUnusedVars adds warnings for the variables defined in the current frame of env and never referred
+func warnUnusedVars(env *model.Env, v *model.Validator) {
+	v.InField("vars", func() {
+		for _, name := range env.GetUnusedNames() {
+			v.InField(name, func() {
+				v.AddWarning("is not used")
+			})
+		}
+	})
+}
@@ -0,0 +1,146 @@
+package template
+
+import (
+	"github.com/autopp/spexec/pkg/matcher"
+	"github.com/autopp/spexec/pkg/matcher/status"
+	"github.com/autopp/spexec/pkg/model"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SpecTemplate", func() {
+	Describe("Expand()", func() {
+		var env *model.Env
+		var v *model.Validator
+		var statusMR *matcher.StatusMatcherRegistry
+		var streamMR *matcher.StreamMatcherRegistry
+
+		JustBeforeEach(func() {
+			env = model.NewEnv(nil)
+			env.Define("greeting", "hello")
+			v, _ = model.NewValidator("", true)
+			statusMR = matcher.NewStatusMatcherRegistry()
+			streamMR = matcher.NewStreamMatcherRegistry()
+		})
+
+		It("expands tests with the spec vars", func() {
+			st := &SpecTemplate{
+				Vars: []*TemplatableVar{
+					{Name: "command", Value: model.NewTemplatableFromValue[any]("echo")},
+					{Name: "message", Value: model.NewTemplatableFromVariable[any]("greeting")},
+				},
+				Tests: []*TestTemplate{
+					{
+						Command: []*model.Templatable[any]{
+							model.NewTemplatableFromVariable[any]("command"),
+							model.NewTemplatableFromVariable[any]("message"),
+						},
+					},
+				},
+			}
+
+			tests, err := st.Expand(env, v, statusMR, streamMR)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(tests).To(HaveLen(1))
+			Expect(tests[0].Command).To(Equal([]model.StringExpr{model.NewLiteralStringExpr("echo"), model.NewLiteralStringExpr("hello")}))
+		})
+
+		It("does not leak the spec vars into the given env", func() {
+			st := &SpecTemplate{
+				Vars:  []*TemplatableVar{{Name: "command", Value: model.NewTemplatableFromValue[any]("echo")}},
+				Tests: []*TestTemplate{},
+			}
+
+			_, err := st.Expand(env, v, statusMR, streamMR)
+			Expect(err).NotTo(HaveOccurred())
+			_, ok := env.Lookup("command")
+			Expect(ok).To(BeFalse())
+		})
+
+		It("prefers the variables defined in the given env", func() {
+			st := &SpecTemplate{
+				Vars: []*TemplatableVar{{Name: "greeting", Value: model.NewTemplatableFromValue[any]("hi")}},
+				Tests: []*TestTemplate{
+					{
+						Command: []*model.Templatable[any]{model.NewTemplatableFromVariable[any]("greeting")},
+					},
+				},
+			}
+
+			tests, err := st.Expand(env, v, statusMR, streamMR)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(tests[0].Command).To(Equal([]model.StringExpr{model.NewLiteralStringExpr("hello")}))
+		})
+
+		It("adds warnings for unused vars, tests without expect and duplicated test names", func() {
+			st := &SpecTemplate{
+				Vars: []*TemplatableVar{
+					{Name: "command", Value: model.NewTemplatableFromValue[any]("echo")},
+					{Name: "unused", Value: model.NewTemplatableFromValue[any]("hi")},
+				},
+				Tests: []*TestTemplate{
+					{
+						Name:          model.NewTemplatableFromValue("hello"),
+						Command:       []*model.Templatable[any]{model.NewTemplatableFromVariable[any]("command")},
+						StatusMatcher: model.NewTemplatableFromValue[any](model.Map{"success": true}),
+					},
+					{
+						Name:    model.NewTemplatableFromValue("hello"),
+						Command: []*model.Templatable[any]{model.NewTemplatableFromValue[any]("true")},
+					},
+				},
+			}
+			statusMR = status.NewStatusMatcherRegistryWithBuiltins()
+
+			_, err := st.Expand(env, v, statusMR, streamMR)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(v.Warnings()).To(Equal([]*model.Violation{
+				{Filename: "<stdin>", Path: "$.tests[1]", Message: "has no expect"},
+				{Filename: "<stdin>", Path: "$.tests[1]", Message: `name "hello" is duplicated`},
+				{Filename: "<stdin>", Path: "$.vars.unused", Message: "is not used"},
+			}))
+		})
+
+		It("adds violation without panic when a command contains non-string item", func() {
+			st := &SpecTemplate{
+				Tests: []*TestTemplate{
+					{
+						Command:       []*model.Templatable[any]{model.NewTemplatableFromValue[any](true)},
+						StatusMatcher: model.NewTemplatableFromValue[any](model.Map{"success": true}),
+					},
+				},
+			}
+			statusMR = status.NewStatusMatcherRegistryWithBuiltins()
+
+			_, err := st.Expand(env, v, statusMR, streamMR)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(v.Error()).To(MatchError("$.tests[0].command[0]: should be string or map, but is bool"))
+		})
+
+		It("returns error with the path of the test when a test refers an undefined variable", func() {
+			st := &SpecTemplate{
+				Tests: []*TestTemplate{
+					{
+						Command: []*model.Templatable[any]{
+							model.NewTemplatableFromValue[any]("echo"),
+							model.NewTemplatableFromVariable[any]("undefined"),
+						},
+					},
+				},
+			}
+
+			_, err := st.Expand(env, v, statusMR, streamMR)
+			Expect(err).To(MatchError("$.tests[0].command[1].$undefined: is not defined"))
+		})
+
+		It("returns error when a var refers an undefined variable", func() {
+			st := &SpecTemplate{
+				Vars:  []*TemplatableVar{{Name: "command", Value: model.NewTemplatableFromVariable[any]("undefined")}},
+				Tests: []*TestTemplate{},
+			}
+
+			_, err := st.Expand(env, v, statusMR, streamMR)
+			Expect(err).To(MatchError("$.vars.command.$undefined: is not defined"))
+		})
+	})
+})
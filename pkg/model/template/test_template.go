@@ -15,6 +15,9 @@
 package template
 
 import (
+	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/autopp/spexec/pkg/errors"
@@ -32,6 +35,7 @@ type TemplatableStringVar struct {
 type TestTemplate struct {
 	Name          *model.Templatable[string]
 	SpecFilename  string
+	Line          int
 	Dir           string
 	Command       []*model.Templatable[any]
 	Stdin         *model.Templatable[any]
@@ -42,94 +46,232 @@ type TestTemplate struct {
 	Timeout       time.Duration
 	TeeStdout     bool
 	TeeStderr     bool
+	Serial        bool
+	Tags          []string
+	SkipReason    string
+	PendingReason string
+	Focus         bool
+	SkipIf        []*ConditionTemplate
+	OnlyIf        []*ConditionTemplate
+	Rows          []model.Map
+	// DefaultEnv is inherited from the spec and groups, and precedes Env
+	DefaultEnv []*TemplatableStringVar
 }
 
-// TODO: set validator path
-func (tt *TestTemplate) Expand(env *model.Env, v *model.Validator, statusMR *matcher.StatusMatcherRegistry, streamMR *matcher.StreamMatcherRegistry) (*model.Test, error) {
-	name := ""
-	if tt.Name != nil {
-		var err error
-		name, err = tt.Name.Expand(env, v)
+// ExpandAll expands the test once for each row with the row values bound as variables.
+// When the test has no rows, it is expanded once in env.
+func (tt *TestTemplate) ExpandAll(env *model.Env, v *model.Validator, statusMR *matcher.StatusMatcherRegistry, streamMR *matcher.StreamMatcherRegistry) ([]*model.Test, error) {
+	if tt.StatusMatcher == nil && tt.StdoutMatcher == nil && tt.StderrMatcher == nil {
+		v.AddWarning("has no expect")
+	}
+
+	if len(tt.Rows) == 0 {
+		t, err := tt.Expand(env, v, statusMR, streamMR)
 		if err != nil {
 			return nil, err
 		}
+		return []*model.Test{t}, nil
 	}
 
-	command := make([]model.StringExpr, 0, len(tt.Command))
-	for _, ct := range tt.Command {
-		x, err := ct.Expand(env, v)
+	tests := make([]*model.Test, 0, len(tt.Rows))
+	for _, row := range tt.Rows {
+		rowEnv := model.NewEnv(env)
+		names := sortedKeys(row)
+		for _, name := range names {
+			rowEnv.Define(name, row[name])
+		}
+
+		t, err := tt.Expand(rowEnv, v, statusMR, streamMR)
 		if err != nil {
 			return nil, err
 		}
 
-		// TODO: error handling
-		c, _ := v.MustBeStringExpr(x)
-		command = append(command, c)
+		// Name given as template is expected to contain the row values by itself
+		if tt.Name == nil || !tt.Name.IsTemplate() {
+			values := make([]string, len(names))
+			for i, name := range names {
+				values[i] = fmt.Sprintf("%s: %v", name, row[name])
+			}
+			// the default name is the command line
+			t.Name = fmt.Sprintf("%s (%s)", t.GetDescription(), strings.Join(values, ", "))
+		}
+		tests = append(tests, t)
 	}
 
-	evaledStdin := []byte("")
-	if tt.Stdin != nil {
-		stdin, err := tt.Stdin.Expand(env, v)
+	return tests, nil
+}
+
+func sortedKeys(m model.Map) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+func (tt *TestTemplate) Expand(env *model.Env, v *model.Validator, statusMR *matcher.StatusMatcherRegistry, streamMR *matcher.StreamMatcherRegistry) (*model.Test, error) {
+	var err error
+	name := ""
+	if tt.Name != nil {
+		v.InField("name", func() {
+			name, err = tt.Name.Expand(env, v)
+		})
 		if err != nil {
 			return nil, err
 		}
-		evaledStdin = evalCommandStdin(v, stdin)
-		if evaledStdin == nil {
-			// TODO: error handling
-			return nil, errors.New(errors.ErrInvalidSpec, "cannot load stdin")
-		}
 	}
 
-	var statusMatcher model.StatusMatcher
-	if tt.StatusMatcher != nil {
-		status, err := tt.StatusMatcher.Expand(env, v)
-		if err != nil {
-			return nil, err
+	command := make([]model.StringExpr, 0, len(tt.Command))
+	v.InField("command", func() {
+		for i, ct := range tt.Command {
+			v.InIndex(i, func() {
+				var x any
+				x, err = ct.Expand(env, v)
+				if err != nil {
+					return
+				}
+
+				if c, ok := v.MustBeStringExpr(x); ok {
+					command = append(command, c)
+				}
+			})
+			if err != nil {
+				return
+			}
 		}
-		statusMatcher = statusMR.ParseMatcher(v, status)
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	var stdoutMatcher model.StreamMatcher
-	if tt.StdoutMatcher != nil {
-		stdout, err := tt.StdoutMatcher.Expand(env, v)
+	evaledStdin := []byte("")
+	if tt.Stdin != nil {
+		v.InField("stdin", func() {
+			var stdin any
+			stdin, err = tt.Stdin.Expand(env, v)
+			if err != nil {
+				return
+			}
+			evaledStdin = evalCommandStdin(v, stdin)
+			if evaledStdin == nil {
+				// TODO: error handling
+				err = errors.New(errors.ErrInvalidSpec, "cannot load stdin")
+			}
+		})
 		if err != nil {
 			return nil, err
 		}
-		stdoutMatcher = streamMR.ParseMatcher(v, stdout)
 	}
 
+	var statusMatcher model.StatusMatcher
+	var stdoutMatcher model.StreamMatcher
 	var stderrMatcher model.StreamMatcher
-	if tt.StderrMatcher != nil {
-		stderr, err := tt.StderrMatcher.Expand(env, v)
-		if err != nil {
-			return nil, err
+	expect := model.Map{}
+	v.InField("expect", func() {
+		if tt.StatusMatcher != nil {
+			v.InField("status", func() {
+				var status any
+				status, err = tt.StatusMatcher.Expand(env, v)
+				if err == nil {
+					expect["status"] = status
+					statusMatcher = statusMR.ParseMatcher(v, status)
+				}
+			})
+			if err != nil {
+				return
+			}
+		}
+
+		if tt.StdoutMatcher != nil {
+			v.InField("stdout", func() {
+				var stdout any
+				stdout, err = tt.StdoutMatcher.Expand(env, v)
+				if err == nil {
+					expect["stdout"] = stdout
+					stdoutMatcher = streamMR.ParseMatcher(v, stdout)
+				}
+			})
+			if err != nil {
+				return
+			}
 		}
-		stderrMatcher = streamMR.ParseMatcher(v, stderr)
+
+		if tt.StderrMatcher != nil {
+			v.InField("stderr", func() {
+				var stderr any
+				stderr, err = tt.StderrMatcher.Expand(env, v)
+				if err == nil {
+					expect["stderr"] = stderr
+					stderrMatcher = streamMR.ParseMatcher(v, stderr)
+				}
+			})
+		}
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	tEnv := make([]util.StringVar, 0, len(tt.Env))
-	for _, tsv := range tt.Env {
-		value, err := tsv.Value.Expand(env, v)
+	tEnv := make([]util.StringVar, 0, len(tt.DefaultEnv)+len(tt.Env))
+	for _, tsv := range tt.DefaultEnv {
+		var value string
+		value, err = tsv.Value.Expand(env, v)
 		if err != nil {
 			return nil, err
 		}
-
 		tEnv = append(tEnv, util.StringVar{Name: tsv.Name, Value: value})
 	}
+	v.InField("env", func() {
+		for i, tsv := range tt.Env {
+			v.InIndex(i, func() {
+				v.InField("value", func() {
+					var value string
+					value, err = tsv.Value.Expand(env, v)
+					tEnv = append(tEnv, util.StringVar{Name: tsv.Name, Value: value})
+				})
+			})
+			if err != nil {
+				return
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	skipIf, err := expandConditions(env, v, "skipIf", tt.SkipIf)
+	if err != nil {
+		return nil, err
+	}
+
+	onlyIf, err := expandConditions(env, v, "onlyIf", tt.OnlyIf)
+	if err != nil {
+		return nil, err
+	}
 
 	return &model.Test{
 		Name:          name,
 		SpecFilename:  tt.SpecFilename,
+		Line:          tt.Line,
 		Dir:           tt.Dir,
 		Command:       command,
 		Stdin:         evaledStdin,
 		StatusMatcher: statusMatcher,
 		StdoutMatcher: stdoutMatcher,
 		StderrMatcher: stderrMatcher,
+		Expect:        expect,
 		Env:           tEnv,
 		Timeout:       tt.Timeout,
 		TeeStdout:     tt.TeeStdout,
 		TeeStderr:     tt.TeeStderr,
+		Serial:        tt.Serial,
+		Tags:          tt.Tags,
+		SkipReason:    tt.SkipReason,
+		PendingReason: tt.PendingReason,
+		Focus:         tt.Focus,
+		SkipIf:        skipIf,
+		OnlyIf:        onlyIf,
 	}, nil
 }
 
@@ -69,6 +69,7 @@ var _ = Describe("TestTemplate", func() {
 					StatusMatcher: testutil.NewExampleStatusMatcher(true, "message", nil),
 					StdoutMatcher: testutil.NewExampleStreamMatcher(true, "message", nil),
 					StderrMatcher: testutil.NewExampleStreamMatcher(true, "message", nil),
+					Expect:        model.Map{"status": model.Map{"statusExample": nil}, "stdout": model.Map{"streamExample": nil}, "stderr": model.Map{"streamExample": nil}},
 					TeeStdout:     false,
 					TeeStderr:     false,
 					Timeout:       1 * time.Second,
@@ -106,6 +107,7 @@ var _ = Describe("TestTemplate", func() {
 					StatusMatcher: testutil.NewExampleStatusMatcher(true, "message", nil),
 					StdoutMatcher: testutil.NewExampleStreamMatcher(true, "message", nil),
 					StderrMatcher: testutil.NewExampleStreamMatcher(true, "message", nil),
+					Expect:        model.Map{"status": model.Map{"statusExample": nil}, "stdout": model.Map{"streamExample": nil}, "stderr": model.Map{"streamExample": nil}},
 					TeeStdout:     false,
 					TeeStderr:     false,
 					Timeout:       1 * time.Second,
@@ -113,6 +115,93 @@ var _ = Describe("TestTemplate", func() {
 			),
 		)
 	})
+
+	Describe("ExpandAll()", func() {
+		var env *model.Env
+		var v *model.Validator
+		var statusMR *matcher.StatusMatcherRegistry
+		var streamMR *matcher.StreamMatcherRegistry
+
+		JustBeforeEach(func() {
+			env = model.NewEnv(nil)
+			v, _ = model.NewValidator("", true)
+			statusMR = matcher.NewStatusMatcherRegistry()
+			streamMR = matcher.NewStreamMatcherRegistry()
+		})
+
+		It("expands once without rows", func() {
+			tt := &TestTemplate{
+				Name:    model.NewTemplatableFromValue("test"),
+				Command: []*model.Templatable[any]{model.NewTemplatableFromValue[any]("echo")},
+			}
+
+			tests, err := tt.ExpandAll(env, v, statusMR, streamMR)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(tests).To(HaveLen(1))
+			Expect(tests[0].Name).To(Equal("test"))
+		})
+
+		It("expands for each row and renders the row values into the name", func() {
+			tt := &TestTemplate{
+				Name: model.NewTemplatableFromValue("test"),
+				Command: []*model.Templatable[any]{
+					model.NewTemplatableFromVariable[any]("a"),
+					model.NewTemplatableFromVariable[any]("b"),
+				},
+				Rows: []model.Map{{"a": "echo", "b": "hello"}, {"a": "printf", "b": "world"}},
+			}
+
+			tests, err := tt.ExpandAll(env, v, statusMR, streamMR)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(tests).To(HaveLen(2))
+			Expect(tests[0].Name).To(Equal("test (a: echo, b: hello)"))
+			Expect(tests[0].Command).To(Equal([]model.StringExpr{model.NewLiteralStringExpr("echo"), model.NewLiteralStringExpr("hello")}))
+			Expect(tests[1].Name).To(Equal("test (a: printf, b: world)"))
+			Expect(tests[1].Command).To(Equal([]model.StringExpr{model.NewLiteralStringExpr("printf"), model.NewLiteralStringExpr("world")}))
+		})
+
+		It("renders the row values into the command line when the name is not given", func() {
+			tt := &TestTemplate{
+				Command: []*model.Templatable[any]{model.NewTemplatableFromValue[any]("cat")},
+				Stdin:   model.NewTemplatableFromVariable[any]("input"),
+				Rows:    []model.Map{{"input": "hello"}, {"input": "world"}},
+			}
+
+			tests, err := tt.ExpandAll(env, v, statusMR, streamMR)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(tests[0].Name).To(Equal("cat (input: hello)"))
+			Expect(tests[1].Name).To(Equal("cat (input: world)"))
+		})
+
+		It("does not change the name given as template", func() {
+			tt := &TestTemplate{
+				Name:    model.NewTemplatableFromText[string]("says {{.Var.message}}"),
+				Command: []*model.Templatable[any]{model.NewTemplatableFromValue[any]("echo")},
+				Rows:    []model.Map{{"message": "hello"}},
+			}
+
+			tests, err := tt.ExpandAll(env, v, statusMR, streamMR)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(tests[0].Name).To(Equal("says hello"))
+		})
+
+		It("puts the default env before the env of the test and reports errors of env with its own index", func() {
+			tt := &TestTemplate{
+				Name:       model.NewTemplatableFromValue("test"),
+				Command:    []*model.Templatable[any]{model.NewTemplatableFromValue[any]("echo")},
+				DefaultEnv: []*TemplatableStringVar{{Name: "LANG", Value: model.NewTemplatableFromValue("C")}},
+				Env:        []*TemplatableStringVar{{Name: "MESSAGE", Value: model.NewTemplatableFromValue("hello")}},
+			}
+
+			tests, err := tt.ExpandAll(env, v, statusMR, streamMR)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(tests[0].Env).To(Equal([]util.StringVar{{Name: "LANG", Value: "C"}, {Name: "MESSAGE", Value: "hello"}}))
+
+			tt.Env = []*TemplatableStringVar{{Name: "MESSAGE", Value: model.NewTemplatableFromVariable[string]("undefined")}}
+			_, err = tt.ExpandAll(env, v, statusMR, streamMR)
+			Expect(err).To(MatchError("$.env[0].value.$undefined: is not defined"))
+		})
+	})
 })
 
 var _ = Describe("evalCommandStdin", func() {
@@ -73,6 +73,24 @@ var _ = Describe("TemplateText", func() {
 			Expect(err).NotTo(HaveOccurred())
 			Expect(actual).To(Equal("answer is 42"))
 		})
+
+		DescribeTable("marks referred variables as used",
+			func(text string, expected []string) {
+				env := NewEnv(nil)
+				env.Define("answer", "42")
+				env.Define("question", "?")
+				v, _ := NewValidator("", true)
+
+				_, _, err := NewTemplateText(text).Expand(env, v, text)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(env.GetUnusedNames()).To(Equal(expected))
+			},
+			Entry("with field", "{{ .Var.answer }}", []string{"question"}),
+			Entry("with field in if", "{{ if .Var.answer }}{{ else }}{{ .Var.question }}{{ end }}", []string{}),
+			Entry("with field of $", "{{ $.Var.answer }}", []string{"question"}),
+			Entry("without reference", "answer is 42", []string{"answer", "question"}),
+			Entry("with whole .Var", `{{ index .Var "answer" }}`, []string{}),
+		)
 	})
 })
 
@@ -16,6 +16,7 @@ package model
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/Wing924/shellwords"
@@ -25,20 +26,39 @@ import (
 
 type Test struct {
 	Name          string
+	Group         *Group
 	SpecFilename  string
+	Line          int
 	Dir           string
 	Command       []StringExpr
 	Stdin         []byte
 	StatusMatcher StatusMatcher
 	StdoutMatcher StreamMatcher
 	StderrMatcher StreamMatcher
+	Expect        Map // expanded matcher specifiers to describe the matchers
 	Env           []util.StringVar
 	Timeout       time.Duration
 	TeeStdout     bool
 	TeeStderr     bool
+	Serial        bool
+	Tags          []string
+	SkipReason    string
+	PendingReason string
+	Focus         bool
+	SkipIf        []Condition
+	OnlyIf        []Condition
 }
 
+// NoReasonGiven is the reason of skip or pending given without reason
+const NoReasonGiven = "No reason given"
+
+// GetName returns the description of the test prefixed with the names of its groups
 func (t *Test) GetName() string {
+	return strings.Join(append(t.Group.GetNames(), t.GetDescription()), " ")
+}
+
+// GetDescription returns the name of the test, or the command line when the name is not given
+func (t *Test) GetDescription() string {
 	if len(t.Name) != 0 {
 		return t.Name
 	}
@@ -56,6 +76,88 @@ func (t *Test) GetName() string {
 	return envStr + shellwords.Join(command)
 }
 
+// IsSkipped returns whether the test should not be run because it is skipped or pending
+func (t *Test) IsSkipped() bool {
+	return len(t.SkipReason) != 0 || t.IsPending()
+}
+
+// IsPending returns whether the test is marked as pending
+func (t *Test) IsPending() bool {
+	return len(t.PendingReason) != 0
+}
+
+// HasTag returns whether the test has the given tag
+func (t *Test) HasTag(tag string) bool {
+	for _, x := range t.Tags {
+		if x == tag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Skip marks the test as skipped with reason
+func (t *Test) Skip(reason string) {
+	t.SkipReason = reason
+}
+
+// EvalConditions marks the test as skipped when one of SkipIf holds or one of OnlyIf does not hold
+func (t *Test) EvalConditions() error {
+	for _, c := range t.SkipIf {
+		holds, description, err := c.Check()
+		if err != nil {
+			return err
+		}
+		if holds {
+			t.Skip("skipIf: " + description)
+			return nil
+		}
+	}
+
+	for _, c := range t.OnlyIf {
+		holds, description, err := c.Check()
+		if err != nil {
+			return err
+		}
+		if !holds {
+			t.Skip("onlyIf: " + description)
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// SkippedResult returns the result of the test which is not run
+func (t *Test) SkippedResult() *TestResult {
+	status, reason := TestSkipped, t.SkipReason
+	if t.IsPending() {
+		status, reason = TestPending, t.PendingReason
+	}
+
+	return &TestResult{
+		Name:       t.GetName(),
+		Groups:     t.Group.GetNames(),
+		Status:     status,
+		SkipReason: reason,
+		Messages:   make([]*AssertionMessage, 0),
+		IsSuccess:  true,
+	}
+}
+
+// NotRunResult returns the result of the test which is not run because the run is aborted
+func (t *Test) NotRunResult(reason string) *TestResult {
+	return &TestResult{
+		Name:       t.GetName(),
+		Groups:     t.Group.GetNames(),
+		Status:     TestNotRun,
+		SkipReason: reason,
+		Messages:   make([]*AssertionMessage, 0),
+		IsSuccess:  true,
+	}
+}
+
 func (t *Test) Run() (*TestResult, error) {
 	command, cleanup, err, _ := EvalStringExprs(t.Command)
 	// FIXME: error handling
@@ -106,9 +208,22 @@ func (t *Test) Run() (*TestResult, error) {
 		}
 	}
 
+	isSuccess := statusOk && stdoutOk && stderrOk
+	status := TestPassed
+	if !isSuccess {
+		status = TestFailed
+	}
+
 	return &TestResult{
-		Name:      t.GetName(),
-		Messages:  messages,
-		IsSuccess: statusOk && stdoutOk && stderrOk,
+		Name:       t.GetName(),
+		Groups:     t.Group.GetNames(),
+		Status:     status,
+		Messages:   messages,
+		IsSuccess:  isSuccess,
+		Duration:   r.Elapsed,
+		UserTime:   r.UserTime,
+		SystemTime: r.SystemTime,
+		Stdout:     r.Stdout,
+		Stderr:     r.Stderr,
 	}, nil
 }
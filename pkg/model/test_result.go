@@ -14,27 +14,96 @@
 
 package model
 
+import (
+	"encoding/json"
+	"sort"
+	"time"
+)
+
 type AssertionMessage struct {
 	Name    string `json:"name"`
 	Message string `json:"message"`
 }
 
+// TestStatus represents the outcome of a test
+type TestStatus string
+
+const (
+	TestPassed  TestStatus = "passed"
+	TestFailed  TestStatus = "failed"
+	TestSkipped TestStatus = "skipped"
+	TestPending TestStatus = "pending"
+	TestNotRun  TestStatus = "notRun"
+)
+
 type TestResult struct {
-	Name      string              `json:"name"`
-	Messages  []*AssertionMessage `json:"messages"`
-	IsSuccess bool                `json:"isSuccess"`
+	Name         string              `json:"name"`
+	Groups       []string            `json:"groups,omitempty"`
+	Status       TestStatus          `json:"status"`
+	SkipReason   string              `json:"skipReason,omitempty"`
+	Messages     []*AssertionMessage `json:"messages"`
+	HookFailures []*AssertionMessage `json:"hookFailures,omitempty"`
+	IsSuccess    bool                `json:"isSuccess"`
+	Duration     time.Duration       `json:"-"`
+	UserTime     time.Duration       `json:"-"`
+	SystemTime   time.Duration       `json:"-"`
+	Stdout       []byte              `json:"-"`
+	Stderr       []byte              `json:"-"`
+}
+
+// MarshalJSON encodes tr with its times in seconds and its streams as strings
+func (tr *TestResult) MarshalJSON() ([]byte, error) {
+	type testResult TestResult
+	return json.Marshal(&struct {
+		*testResult
+		Duration   float64 `json:"duration"`
+		UserTime   float64 `json:"userTime"`
+		SystemTime float64 `json:"systemTime"`
+		Stdout     string  `json:"stdout,omitempty"`
+		Stderr     string  `json:"stderr,omitempty"`
+	}{
+		testResult: (*testResult)(tr),
+		Duration:   tr.Duration.Seconds(),
+		UserTime:   tr.UserTime.Seconds(),
+		SystemTime: tr.SystemTime.Seconds(),
+		Stdout:     string(tr.Stdout),
+		Stderr:     string(tr.Stderr),
+	})
+}
+
+// Fail marks tr as failed
+func (tr *TestResult) Fail() {
+	tr.Status = TestFailed
+	tr.IsSuccess = false
 }
 
 type SpecSummary struct {
 	NumberOfTests     int `json:"numberOfTests"`
 	NumberOfSucceeded int `json:"numberOfSucceeded"`
 	NumberOfFailed    int `json:"numberOfFailed"`
+	NumberOfSkipped   int `json:"numberOfSkipped"`
+	NumberOfPending   int `json:"numberOfPending"`
+	NumberOfNotRun    int `json:"numberOfNotRun"`
 }
 
 type SpecResult struct {
 	Name        string        `json:"name"`
 	TestResults []*TestResult `json:"testResults"`
 	Summary     SpecSummary   `json:"summary"`
+	// Duration is the wall time to run all tests of the spec
+	Duration time.Duration `json:"-"`
+}
+
+// MarshalJSON encodes sr with its duration in seconds
+func (sr *SpecResult) MarshalJSON() ([]byte, error) {
+	type specResult SpecResult
+	return json.Marshal(&struct {
+		*specResult
+		Duration float64 `json:"duration"`
+	}{
+		specResult: (*specResult)(sr),
+		Duration:   sr.Duration.Seconds(),
+	})
 }
 
 func NewSpecResult(name string, testResults []*TestResult) *SpecResult {
@@ -45,7 +114,10 @@ func NewSpecResult(name string, testResults []*TestResult) *SpecResult {
 
 	sr.Summary.NumberOfTests = len(testResults)
 	sr.Summary.NumberOfFailed = len(sr.GetFailedTestResults())
-	sr.Summary.NumberOfSucceeded = sr.Summary.NumberOfTests - sr.Summary.NumberOfFailed
+	sr.Summary.NumberOfSkipped = len(sr.GetSkippedTestResults())
+	sr.Summary.NumberOfPending = len(sr.GetPendingTestResults())
+	sr.Summary.NumberOfNotRun = len(sr.GetNotRunTestResults())
+	sr.Summary.NumberOfSucceeded = sr.Summary.NumberOfTests - sr.Summary.NumberOfFailed - sr.Summary.NumberOfSkipped - sr.Summary.NumberOfPending - sr.Summary.NumberOfNotRun
 	return sr
 }
 
@@ -59,3 +131,118 @@ func (sr *SpecResult) GetFailedTestResults() []*TestResult {
 
 	return failures
 }
+
+func (sr *SpecResult) GetSucceededTestResults() []*TestResult {
+	return sr.filterTestResults(TestPassed)
+}
+
+func (sr *SpecResult) GetSkippedTestResults() []*TestResult {
+	return sr.filterTestResults(TestSkipped)
+}
+
+func (sr *SpecResult) GetPendingTestResults() []*TestResult {
+	return sr.filterTestResults(TestPending)
+}
+
+func (sr *SpecResult) GetNotRunTestResults() []*TestResult {
+	return sr.filterTestResults(TestNotRun)
+}
+
+func (sr *SpecResult) filterTestResults(status TestStatus) []*TestResult {
+	filtered := make([]*TestResult, 0)
+	for _, tr := range sr.TestResults {
+		if tr.Status == status {
+			filtered = append(filtered, tr)
+		}
+	}
+
+	return filtered
+}
+
+// RunResult aggregates the results of all specs in a run
+type RunResult struct {
+	SpecResults []*SpecResult `json:"specResults"`
+	Summary     SpecSummary   `json:"summary"`
+	// Seed is the seed to shuffle tests, which is nil when tests are run in defined order
+	Seed *int64 `json:"seed,omitempty"`
+	// Duration is the wall time to run all specs
+	Duration time.Duration `json:"-"`
+}
+
+// MarshalJSON encodes rr with its duration in seconds
+func (rr *RunResult) MarshalJSON() ([]byte, error) {
+	type runResult RunResult
+	return json.Marshal(&struct {
+		*runResult
+		Duration float64 `json:"duration"`
+	}{
+		runResult: (*runResult)(rr),
+		Duration:  rr.Duration.Seconds(),
+	})
+}
+
+// NewRunResult returns the RunResult of specResults.
+// Its Duration is the sum of the durations of specResults, so the caller should overwrite it with the measured wall time.
+func NewRunResult(specResults []*SpecResult) *RunResult {
+	rr := &RunResult{SpecResults: specResults}
+	for _, sr := range specResults {
+		rr.Duration += sr.Duration
+		rr.Summary.NumberOfTests += sr.Summary.NumberOfTests
+		rr.Summary.NumberOfSucceeded += sr.Summary.NumberOfSucceeded
+		rr.Summary.NumberOfFailed += sr.Summary.NumberOfFailed
+		rr.Summary.NumberOfSkipped += sr.Summary.NumberOfSkipped
+		rr.Summary.NumberOfPending += sr.Summary.NumberOfPending
+		rr.Summary.NumberOfNotRun += sr.Summary.NumberOfNotRun
+	}
+
+	return rr
+}
+
+func (rr *RunResult) GetSucceededTestResults() []*TestResult {
+	return rr.collectTestResults((*SpecResult).GetSucceededTestResults)
+}
+
+func (rr *RunResult) GetFailedTestResults() []*TestResult {
+	return rr.collectTestResults((*SpecResult).GetFailedTestResults)
+}
+
+func (rr *RunResult) collectTestResults(get func(sr *SpecResult) []*TestResult) []*TestResult {
+	collected := make([]*TestResult, 0)
+	for _, sr := range rr.SpecResults {
+		collected = append(collected, get(sr)...)
+	}
+
+	return collected
+}
+
+// IsSuccess returns whether all tests in the run are succeeded
+func (rr *RunResult) IsSuccess() bool {
+	return rr.Summary.NumberOfFailed == 0
+}
+
+// SpecTestResult is a test result with the name of its spec
+type SpecTestResult struct {
+	SpecName string
+	*TestResult
+}
+
+// SlowestTestResults returns at most n test results in descending order of duration
+func (rr *RunResult) SlowestTestResults(n int) []SpecTestResult {
+	results := make([]SpecTestResult, 0)
+	for _, sr := range rr.SpecResults {
+		for _, tr := range sr.TestResults {
+			if tr.Status == TestPassed || tr.Status == TestFailed {
+				results = append(results, SpecTestResult{SpecName: sr.Name, TestResult: tr})
+			}
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Duration > results[j].Duration
+	})
+	if len(results) > n {
+		results = results[:n]
+	}
+
+	return results
+}
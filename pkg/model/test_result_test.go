@@ -1,6 +1,9 @@
 package model
 
 import (
+	"encoding/json"
+	"time"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
@@ -21,12 +24,30 @@ var _ = Describe("SpecResult", func() {
 					Name:      "test3",
 					IsSuccess: false,
 				},
+				{
+					Name:      "test4",
+					Status:    TestSkipped,
+					IsSuccess: true,
+				},
+				{
+					Name:      "test5",
+					Status:    TestPending,
+					IsSuccess: true,
+				},
+				{
+					Name:      "test6",
+					Status:    TestNotRun,
+					IsSuccess: true,
+				},
 			}
 			sr := NewSpecResult("test.yaml", trs)
 			Expect(sr.Summary).To(Equal(SpecSummary{
-				NumberOfTests:     3,
+				NumberOfTests:     6,
 				NumberOfSucceeded: 1,
 				NumberOfFailed:    2,
+				NumberOfSkipped:   1,
+				NumberOfPending:   1,
+				NumberOfNotRun:    1,
 			}))
 		})
 	})
@@ -52,3 +73,66 @@ var _ = Describe("SpecResult", func() {
 		})
 	})
 })
+
+var _ = Describe("RunResult", func() {
+	Describe("NewRunResult()", func() {
+		It("returns RunResult with the sum of summaries", func() {
+			sr1 := NewSpecResult("a.yaml", []*TestResult{
+				{Name: "test1", Status: TestPassed, IsSuccess: true},
+				{Name: "test2", Status: TestFailed, IsSuccess: false},
+			})
+			sr2 := NewSpecResult("b.yaml", []*TestResult{
+				{Name: "test3", Status: TestSkipped, IsSuccess: true},
+			})
+			rr := NewRunResult([]*SpecResult{sr1, sr2})
+			Expect(rr.SpecResults).To(Equal([]*SpecResult{sr1, sr2}))
+			Expect(rr.Summary).To(Equal(SpecSummary{
+				NumberOfTests:     3,
+				NumberOfSucceeded: 1,
+				NumberOfFailed:    1,
+				NumberOfSkipped:   1,
+			}))
+			Expect(rr.IsSuccess()).To(BeFalse())
+		})
+	})
+
+	Describe("GetFailedTestResults()", func() {
+		It("returns failed test results of all specs", func() {
+			trs1 := []*TestResult{{Name: "test1", Status: TestFailed}, {Name: "test2", Status: TestPassed, IsSuccess: true}}
+			trs2 := []*TestResult{{Name: "test3", Status: TestFailed}}
+			rr := NewRunResult([]*SpecResult{NewSpecResult("a.yaml", trs1), NewSpecResult("b.yaml", trs2)})
+			Expect(rr.GetFailedTestResults()).To(Equal([]*TestResult{trs1[0], trs2[0]}))
+			Expect(rr.GetSucceededTestResults()).To(Equal([]*TestResult{trs1[1]}))
+		})
+	})
+
+	Describe("SlowestTestResults()", func() {
+		It("returns the run tests in descending order of duration", func() {
+			trs := []*TestResult{
+				{Name: "test1", Status: TestPassed, IsSuccess: true, Duration: 1 * time.Second},
+				{Name: "test2", Status: TestSkipped, IsSuccess: true},
+				{Name: "test3", Status: TestFailed, IsSuccess: false, Duration: 3 * time.Second},
+				{Name: "test4", Status: TestPassed, IsSuccess: true, Duration: 2 * time.Second},
+			}
+			rr := NewRunResult([]*SpecResult{NewSpecResult("a.yaml", trs)})
+			Expect(rr.SlowestTestResults(2)).To(Equal([]SpecTestResult{
+				{SpecName: "a.yaml", TestResult: trs[2]},
+				{SpecName: "a.yaml", TestResult: trs[3]},
+			}))
+		})
+	})
+})
+
+var _ = Describe("TestResult", func() {
+	Describe("MarshalJSON()", func() {
+		It("encodes times in seconds", func() {
+			tr := &TestResult{Name: "test", Status: TestPassed, Messages: []*AssertionMessage{}, IsSuccess: true, Duration: 1500 * time.Millisecond, UserTime: 250 * time.Millisecond, SystemTime: 125 * time.Millisecond}
+			Expect(json.Marshal(tr)).To(MatchJSON(`{"name":"test","status":"passed","messages":[],"isSuccess":true,"duration":1.5,"userTime":0.25,"systemTime":0.125}`))
+		})
+
+		It("encodes streams as strings", func() {
+			tr := &TestResult{Name: "test", Status: TestFailed, Messages: []*AssertionMessage{}, Stdout: []byte("out\n"), Stderr: []byte("err\n")}
+			Expect(json.Marshal(tr)).To(MatchJSON(`{"name":"test","status":"failed","messages":[],"isSuccess":false,"duration":0,"userTime":0,"systemTime":0,"stdout":"out\n","stderr":"err\n"}`))
+		})
+	})
+})
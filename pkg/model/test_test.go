@@ -27,6 +27,11 @@ var _ = Describe("Test", func() {
 				{Name: "GOARCH", Value: "amd64"},
 			},
 		}, "GOOS=linux GOARCH=amd64 make build"),
+		Entry("Group is given", &model.Test{
+			Name:    "test of echo",
+			Group:   &model.Group{Name: "inner", Parent: &model.Group{Name: "outer"}},
+			Command: []model.StringExpr{model.NewLiteralStringExpr("echo"), model.NewLiteralStringExpr("hello")},
+		}, "outer inner test of echo"),
 	)
 
 	Describe("Run()", func() {
@@ -41,7 +46,13 @@ var _ = Describe("Test", func() {
 			func(test *model.Test, expectedMessages []*model.AssertionMessage, expectedIsSuccess bool) {
 				tr, err := test.Run()
 				Expect(err).NotTo(HaveOccurred())
-				Expect(tr).To(Equal(&model.TestResult{Name: test.GetName(), Messages: expectedMessages, IsSuccess: expectedIsSuccess}))
+				Expect(tr.Duration).To(BeNumerically(">", 0))
+				tr.Duration, tr.UserTime, tr.SystemTime, tr.Stdout, tr.Stderr = 0, 0, 0, nil, nil
+				expectedStatus := model.TestPassed
+				if !expectedIsSuccess {
+					expectedStatus = model.TestFailed
+				}
+				Expect(tr).To(Equal(&model.TestResult{Name: test.GetName(), Status: expectedStatus, Messages: expectedMessages, IsSuccess: expectedIsSuccess}))
 			},
 			Entry("no matchers", &model.Test{
 				Name:    "no matchers case",
@@ -93,6 +104,16 @@ var _ = Describe("Test", func() {
 			}, []*model.AssertionMessage{{Name: "status", Message: "process was signaled (terminated)"}}, false),
 		)
 
+		It("captures the streams of the command", func() {
+			test := &model.Test{
+				Command: []model.StringExpr{model.NewLiteralStringExpr("sh"), model.NewLiteralStringExpr("-c"), model.NewLiteralStringExpr("echo out; echo err >&2")},
+			}
+			tr, err := test.Run()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(tr.Stdout)).To(Equal("out\n"))
+			Expect(string(tr.Stderr)).To(Equal("err\n"))
+		})
+
 		DescribeTable("failed cases",
 			func(test *model.Test, expectedErr string) {
 				tr, err := test.Run()
@@ -105,4 +126,53 @@ var _ = Describe("Test", func() {
 			}, "environment variable $undefined is not defined"),
 		)
 	})
+
+	Describe("EvalConditions()", func() {
+		DescribeTable("marks the test as skipped by conditions",
+			func(skipIf []model.Condition, onlyIf []model.Condition, expectedReason string) {
+				t := &model.Test{SkipIf: skipIf, OnlyIf: onlyIf}
+				Expect(t.EvalConditions()).To(Succeed())
+				Expect(t.SkipReason).To(Equal(expectedReason))
+			},
+			Entry("without conditions", nil, nil, ""),
+			Entry("with held skipIf", []model.Condition{&model.ExprCondition{Value: false}, &model.ExprCondition{Value: true}}, nil, "skipIf: expr is true"),
+			Entry("with not held skipIf", []model.Condition{&model.ExprCondition{Value: false}}, nil, ""),
+			Entry("with held onlyIf", nil, []model.Condition{&model.ExprCondition{Value: true}}, ""),
+			Entry("with not held onlyIf", nil, []model.Condition{&model.ExprCondition{Value: true}, &model.ExprCondition{Value: false}}, "onlyIf: expr is false"),
+		)
+	})
+
+	Describe("SkippedResult()", func() {
+		It("returns the skipped result with the reason", func() {
+			t := &model.Test{
+				Name:       "skipped test",
+				Group:      &model.Group{Name: "group"},
+				SkipReason: "not ready",
+			}
+			Expect(t.IsSkipped()).To(BeTrue())
+			Expect(t.SkippedResult()).To(Equal(&model.TestResult{
+				Name:       "group skipped test",
+				Groups:     []string{"group"},
+				Status:     model.TestSkipped,
+				SkipReason: "not ready",
+				Messages:   []*model.AssertionMessage{},
+				IsSuccess:  true,
+			}))
+		})
+
+		It("returns the pending result with the reason", func() {
+			t := &model.Test{
+				Name:          "pending test",
+				PendingReason: "not implemented",
+			}
+			Expect(t.IsSkipped()).To(BeTrue())
+			Expect(t.SkippedResult()).To(Equal(&model.TestResult{
+				Name:       "pending test",
+				Status:     model.TestPending,
+				SkipReason: "not implemented",
+				Messages:   []*model.AssertionMessage{},
+				IsSuccess:  true,
+			}))
+		})
+	})
 })
@@ -31,17 +31,52 @@ import (
 
 var envVarNamePattern = regexp.MustCompile(`^[a-zA-Z_]\w*$`)
 
-type violation struct {
-	path    string
-	message string
+// Violation is a problem found in the spec
+type Violation struct {
+	Filename string `json:"file"`
+	Line     int    `json:"line,omitempty"`
+	Column   int    `json:"column,omitempty"`
+	Path     string `json:"path"`
+	Message  string `json:"message"`
+}
+
+// String returns the violation as "file:line:col: path: message", or "path: message" when the position is unknown
+func (vi *Violation) String() string {
+	if vi.Line == 0 {
+		return vi.Path + ": " + vi.Message
+	}
+
+	return fmt.Sprintf("%s:%d:%d: %s: %s", vi.Filename, vi.Line, vi.Column, vi.Path, vi.Message)
+}
+
+// ValidationError is the error of the violations found in the spec
+type ValidationError struct {
+	Violations []*Violation
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Violations))
+	for i, violation := range e.Violations {
+		messages[i] = violation.String()
+	}
+
+	return strings.Join(messages, "\n")
+}
+
+// Position is a location in the spec file
+type Position struct {
+	Line   int
+	Column int
 }
 
 type Validator struct {
 	Filename   string
 	dir        string
 	paths      []string
-	violations []violation
+	violations []*Violation
+	warnings   []*Violation
 	isStrict   bool
+	positions  map[string]Position
 }
 
 func NewValidator(filename string, isStrict bool) (*Validator, error) {
@@ -65,7 +100,8 @@ func NewValidator(filename string, isStrict bool) (*Validator, error) {
 		Filename:   filename,
 		dir:        dir,
 		paths:      []string{"$"},
-		violations: make([]violation, 0),
+		violations: make([]*Violation, 0),
+		warnings:   make([]*Violation, 0),
 		isStrict:   isStrict,
 	}, nil
 }
@@ -74,6 +110,22 @@ func (v *Validator) GetDir() string {
 	return v.dir
 }
 
+// SetPositions sets the positions of the paths (e.g. "$.tests[0]") in the spec file
+func (v *Validator) SetPositions(positions map[string]Position) {
+	v.positions = positions
+}
+
+// Position returns the position of the current path, or the nearest ancestor path when it is unknown
+func (v *Validator) Position() (Position, bool) {
+	for i := len(v.paths); i > 0; i-- {
+		if pos, ok := v.positions[strings.Join(v.paths[:i], "")]; ok {
+			return pos, true
+		}
+	}
+
+	return Position{}, false
+}
+
 func (v *Validator) pushPath(path string) {
 	v.paths = append(v.paths, path)
 }
@@ -100,8 +152,37 @@ func (v *Validator) InIndex(index int, f func()) {
 }
 
 func (v *Validator) AddViolation(format string, args ...any) {
+	v.violations = append(v.violations, v.newViolation(format, args...))
+}
+
+// AddWarning records a suspicious construct at the current path.
+// Unlike violations, warnings do not make the spec invalid.
+func (v *Validator) AddWarning(format string, args ...any) {
+	v.warnings = append(v.warnings, v.newViolation(format, args...))
+}
+
+// Warnings returns the recorded warnings
+func (v *Validator) Warnings() []*Violation {
+	return v.warnings
+}
+
+func (v *Validator) newViolation(format string, args ...any) *Violation {
 	message := fmt.Sprintf(format, args...)
-	v.violations = append(v.violations, violation{path: strings.Join(v.paths, ""), message: message})
+	violation := &Violation{Filename: v.displayFilename(), Path: strings.Join(v.paths, ""), Message: message}
+	if pos, ok := v.Position(); ok {
+		violation.Line, violation.Column = pos.Line, pos.Column
+	}
+
+	return violation
+}
+
+// displayFilename returns the filename for messages
+func (v *Validator) displayFilename() string {
+	if len(v.Filename) == 0 {
+		return "<stdin>"
+	}
+
+	return util.RelativePath(v.Filename)
 }
 
 func (v *Validator) MayBeMap(x any) (Map, bool) {
@@ -177,6 +258,41 @@ func (v *Validator) MayBeVariable(x any) (string, bool) {
 	return name, true
 }
 
+// IsVariableName reports whether name can be used as a template variable name
+func IsVariableName(name string) bool {
+	return variablePattern.MatchString(name)
+}
+
+func (v *Validator) MustBeVariableName(name string) bool {
+	if !IsVariableName(name) {
+		v.AddViolation("variable name should be match to /%s/", variablePattern.String())
+		return false
+	}
+
+	return true
+}
+
+var tagPattern = regexp.MustCompile(`^[\w.:/-]+$`)
+
+// IsTagName reports whether name can be used as a tag of tests
+func IsTagName(name string) bool {
+	return tagPattern.MatchString(name) && name != "and" && name != "or" && name != "not"
+}
+
+func (v *Validator) MustBeTagName(x any) (string, bool) {
+	name, ok := v.MustBeString(x)
+	if !ok {
+		return "", false
+	}
+
+	if !IsTagName(name) {
+		v.AddViolation("tag should be match to /%s/ and not be and, or, not", tagPattern.String())
+		return "", false
+	}
+
+	return name, true
+}
+
 func (v *Validator) MayBeTemplateText(x any) (string, bool) {
 	q, value, ok := v.MayBeQualified(x)
 	if !ok || q != "$t" {
@@ -643,9 +759,7 @@ func (v *Validator) LastViolation() string {
 		return ""
 	}
 
-	violation := v.violations[n-1]
-
-	return violation.path + ": " + violation.message
+	return v.violations[n-1].String()
 }
 
 func (v *Validator) Error() error {
@@ -653,12 +767,7 @@ func (v *Validator) Error() error {
 		return nil
 	}
 
-	messages := make([]string, len(v.violations))
-	for i, violation := range v.violations {
-		messages[i] = violation.path + ": " + violation.message
-	}
-
-	return errors.New(errors.ErrInvalidSpec, strings.Join(messages, "\n"))
+	return errors.Wrap(errors.ErrInvalidSpec, &ValidationError{Violations: v.violations})
 }
 
 func toInt(x any) (int, bool) {
@@ -76,6 +76,32 @@ var _ = Describe("Validator", func() {
 				Expect(v.Error()).To(BeValidationError("$: error1\n$: error2"))
 			})
 		})
+
+		Context("with positions", func() {
+			It("makes to Error() to return error which contains the position of the violation", func() {
+				v.SetPositions(map[string]Position{"$": {Line: 1, Column: 1}, "$.tests": {Line: 2, Column: 3}})
+				v.InField("tests", func() {
+					v.AddViolation("error")
+				})
+
+				err := v.Error()
+				Expect(err).To(BeValidationError("<stdin>:2:3: $.tests: error"))
+				var ve *ValidationError
+				Expect(errors.As(err, &ve)).To(BeTrue())
+				Expect(ve.Violations).To(Equal([]*Violation{{Filename: "<stdin>", Line: 2, Column: 3, Path: "$.tests", Message: "error"}}))
+			})
+		})
+	})
+
+	Describe("AddWarning() and Warnings()", func() {
+		It("records warnings with the path without making Error() to return error", func() {
+			v.InField("vars", func() {
+				v.AddWarning("%s is not used", "answer")
+			})
+
+			Expect(v.Warnings()).To(Equal([]*Violation{{Filename: "<stdin>", Path: "$.vars", Message: "answer is not used"}}))
+			Expect(v.Error()).To(Succeed())
+		})
 	})
 
 	Describe("InPath()", func() {
@@ -112,6 +138,32 @@ var _ = Describe("Validator", func() {
 		})
 	})
 
+	Describe("Position()", func() {
+		It("returns the position of the current path or its nearest ancestor", func() {
+			position := func() Position {
+				pos, ok := v.Position()
+				Expect(ok).To(BeTrue())
+				return pos
+			}
+			v.SetPositions(map[string]Position{"$": {Line: 1, Column: 1}, "$.tests[0]": {Line: 2, Column: 5}})
+
+			v.InField("tests", func() {
+				v.InIndex(0, func() {
+					Expect(position()).To(Equal(Position{Line: 2, Column: 5}))
+					v.InField("name", func() {
+						Expect(position()).To(Equal(Position{Line: 2, Column: 5}))
+					})
+				})
+				Expect(position()).To(Equal(Position{Line: 1, Column: 1}))
+			})
+		})
+
+		It("returns false without positions", func() {
+			_, ok := v.Position()
+			Expect(ok).To(BeFalse())
+		})
+	})
+
 	Describe("MayBeMap()", func() {
 		Context("with a Map", func() {
 			It("returns the given Map and true", func() {
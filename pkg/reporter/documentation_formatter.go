@@ -16,6 +16,7 @@ package reporter
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/autopp/spexec/pkg/model"
 )
@@ -25,16 +26,30 @@ DocumentationFormatter implements Reporter.
 
 Example of output:
 
-	.F.
+	test1 (0.002s)
+	group
+	  test2 (0.003s)
+	  nested group
+	    test3 (0.001s)
+
+	Finished in 0.012 seconds
 	3 examples, 1 failures
 */
-type DocumentationFormatter struct{}
+type DocumentationFormatter struct {
+	groups []*model.Group
+}
 
 // OnRunStart is part of Reporter
 func (f *DocumentationFormatter) OnRunStart(w *Writer) error {
 	return nil
 }
 
+// OnSpecStart is part of Reporter
+func (f *DocumentationFormatter) OnSpecStart(w *Writer, name string) error {
+	f.groups = nil
+	return nil
+}
+
 // OnTestStart is part of Reporter
 func (f *DocumentationFormatter) OnTestStart(w *Writer, t *model.Test) error {
 	return nil
@@ -43,30 +58,73 @@ func (f *DocumentationFormatter) OnTestStart(w *Writer, t *model.Test) error {
 // OnTestComplete is part of Reporter
 func (f *DocumentationFormatter) OnTestComplete(w *Writer, t *model.Test, tr *model.TestResult) error {
 	var color Color
-	if tr.IsSuccess {
+	suffix := ""
+	if tr.Status == model.TestSkipped {
+		color = Yellow
+		suffix = fmt.Sprintf(" (SKIPPED: %s)", tr.SkipReason)
+	} else if tr.Status == model.TestPending {
+		color = Yellow
+		suffix = fmt.Sprintf(" (PENDING: %s)", tr.SkipReason)
+	} else if tr.IsSuccess {
 		color = Green
+		suffix = formatTestTime(tr)
 	} else {
 		color = Red
+		suffix = formatTestTime(tr)
+	}
+
+	groups := t.Group.GetPath()
+	common := 0
+	for common < len(groups) && common < len(f.groups) && groups[common] == f.groups[common] {
+		common++
+	}
+
+	depth := 0
+	for i, g := range groups {
+		if len(g.Name) == 0 {
+			continue
+		}
+		if i >= common {
+			fmt.Fprintf(w, "%s%s\n", indent(depth), g.Name)
+		}
+		depth++
 	}
+	f.groups = groups
 
 	w.UseColor(color, func() {
-		fmt.Fprintln(w, t.GetName())
+		fmt.Fprintf(w, "%s%s%s\n", indent(depth), t.GetDescription(), suffix)
 	})
+	// outputs of failed tests are printed with failures
+	if tr.IsSuccess {
+		printOutput(w, tr, depth+1)
+	}
 
 	return nil
 }
 
+func indent(depth int) string {
+	return strings.Repeat("  ", depth)
+}
+
+// OnSpecComplete is part of Reporter
+func (f *DocumentationFormatter) OnSpecComplete(w *Writer, sr *model.SpecResult) error {
+	return nil
+}
+
 // OnRunComplete is part of Reporter
-func (f *DocumentationFormatter) OnRunComplete(w *Writer, sr *model.SpecResult) error {
-	failed := sr.GetFailedTestResults()
+func (f *DocumentationFormatter) OnRunComplete(w *Writer, rr *model.RunResult) error {
+	failed := rr.GetFailedTestResults()
 	var color Color = Green
 	if len(failed) > 0 {
 		color = Red
 	}
 	printFailures(w, failed)
+	fmt.Fprintf(w, "\nFinished in %s seconds\n", formatSeconds(rr.Duration))
 	w.UseColor(color, func() {
-		fmt.Fprintf(w, "\n%d examples, %d failures\n", sr.Summary.NumberOfSucceeded, sr.Summary.NumberOfFailed)
+		fmt.Fprintf(w, "%d examples, %d failures%s\n", rr.Summary.NumberOfTests, rr.Summary.NumberOfFailed, formatNotRun(rr.Summary))
 	})
 
 	return nil
 }
+
+func (f *DocumentationFormatter) isTextFormatter() {}
@@ -0,0 +1,58 @@
+package reporter
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/autopp/spexec/pkg/model"
+	g "github.com/onsi/ginkgo/v2" // Reporter are duplicated
+	. "github.com/onsi/gomega"
+)
+
+var _ = g.Describe("DocumentationFormatter", func() {
+	g.It("writes tests with their time and counts all tests as examples", func() {
+		buf := &bytes.Buffer{}
+		w := newWriter(buf, false)
+		f := &DocumentationFormatter{}
+
+		trs := make([]*model.TestResult, 0, 7)
+		Expect(f.OnRunStart(w)).To(Succeed())
+		Expect(f.OnSpecStart(w, "spec.yaml")).To(Succeed())
+		for i := 0; i < 7; i++ {
+			t := &model.Test{Name: fmt.Sprintf("test%d", i+1), Group: &model.Group{}}
+			tr := &model.TestResult{Name: t.Name, Status: model.TestPassed, IsSuccess: true, Duration: 10 * time.Millisecond}
+			if i%2 == 1 {
+				tr.Status = model.TestFailed
+				tr.IsSuccess = false
+			}
+			trs = append(trs, tr)
+			Expect(f.OnTestComplete(w, t, tr)).To(Succeed())
+		}
+		sr := model.NewSpecResult("spec.yaml", trs)
+		Expect(f.OnSpecComplete(w, sr)).To(Succeed())
+		rr := model.NewRunResult([]*model.SpecResult{sr})
+		rr.Duration = 70 * time.Millisecond
+		Expect(f.OnRunComplete(w, rr)).To(Succeed())
+
+		Expect(buf.String()).To(Equal(`test1 (0.010s)
+test2 (0.010s)
+test3 (0.010s)
+test4 (0.010s)
+test5 (0.010s)
+test6 (0.010s)
+test7 (0.010s)
+
+Failures:
+
+  1) test2 (0.010s)
+
+  2) test4 (0.010s)
+
+  3) test6 (0.010s)
+
+Finished in 0.070 seconds
+7 examples, 3 failures
+`))
+	})
+})
@@ -0,0 +1,197 @@
+// Copyright (C) 2021-2023	 Akira Tanimura (@autopp)
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reporter
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/autopp/spexec/pkg/model"
+	"github.com/autopp/spexec/pkg/util"
+)
+
+/*
+GitHubFormatter implements Reporter.
+
+It writes workflow commands of GitHub Actions.
+Tests of each spec are folded into a group and failed tests are annotated at their location in the spec.
+
+Example of output:
+
+	::group::spec.yaml
+	PASS test1 (0.002s)
+	FAIL test2 (0.003s)
+	::error file=spec.yaml,line=5,title=test2::status: should succeed, but not
+	SKIP test3 (not ready)
+	::endgroup::
+	3 examples, 1 failures, 1 skipped
+*/
+type GitHubFormatter struct {
+	// StepSummary is the path of the file which the Markdown summary of the run is appended to (e.g. $GITHUB_STEP_SUMMARY).
+	// The summary is not written when it is empty.
+	StepSummary string
+	failures    []*githubFailure
+}
+
+type githubFailure struct {
+	file string
+	line int
+	tr   *model.TestResult
+}
+
+// OnRunStart is part of Reporter
+func (f *GitHubFormatter) OnRunStart(w *Writer) error {
+	f.failures = nil
+	return nil
+}
+
+// OnSpecStart is part of Reporter
+func (f *GitHubFormatter) OnSpecStart(w *Writer, name string) error {
+	_, err := fmt.Fprintf(w, "::group::%s\n", escapeGitHubData(name))
+	return err
+}
+
+// OnTestStart is part of Reporter
+func (f *GitHubFormatter) OnTestStart(w *Writer, t *model.Test) error {
+	return nil
+}
+
+// OnTestComplete is part of Reporter
+func (f *GitHubFormatter) OnTestComplete(w *Writer, t *model.Test, tr *model.TestResult) error {
+	switch {
+	case tr.Status == model.TestSkipped:
+		w.UseColor(Yellow, func() {
+			fmt.Fprintf(w, "SKIP %s (%s)\n", tr.Name, tr.SkipReason)
+		})
+	case tr.Status == model.TestPending:
+		w.UseColor(Yellow, func() {
+			fmt.Fprintf(w, "PENDING %s (%s)\n", tr.Name, tr.SkipReason)
+		})
+	case tr.IsSuccess:
+		w.UseColor(Green, func() {
+			fmt.Fprintf(w, "PASS %s%s\n", tr.Name, formatTestTime(tr))
+		})
+	default:
+		w.UseColor(Red, func() {
+			fmt.Fprintf(w, "FAIL %s%s\n", tr.Name, formatTestTime(tr))
+		})
+
+		failure := &githubFailure{file: util.RelativePath(t.SpecFilename), line: t.Line, tr: tr}
+		f.failures = append(f.failures, failure)
+		properties := make([]string, 0, 3)
+		if len(failure.file) != 0 {
+			properties = append(properties, "file="+escapeGitHubProperty(failure.file))
+			if failure.line != 0 {
+				properties = append(properties, fmt.Sprintf("line=%d", failure.line))
+			}
+		}
+		properties = append(properties, "title="+escapeGitHubProperty(tr.Name))
+		if _, err := fmt.Fprintf(w, "::error %s::%s\n", strings.Join(properties, ","), escapeGitHubData(strings.Join(failureLines(tr), "\n"))); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// OnSpecComplete is part of Reporter
+func (f *GitHubFormatter) OnSpecComplete(w *Writer, sr *model.SpecResult) error {
+	_, err := fmt.Fprintln(w, "::endgroup::")
+	return err
+}
+
+// OnRunComplete is part of Reporter
+func (f *GitHubFormatter) OnRunComplete(w *Writer, rr *model.RunResult) error {
+	if _, err := fmt.Fprintf(w, "%d examples, %d failures%s\n", rr.Summary.NumberOfTests, rr.Summary.NumberOfFailed, formatNotRun(rr.Summary)); err != nil {
+		return err
+	}
+
+	if len(f.StepSummary) == 0 {
+		return nil
+	}
+
+	summary, err := os.OpenFile(f.StepSummary, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer summary.Close()
+
+	_, err = summary.WriteString(f.stepSummary(rr))
+	return err
+}
+
+func (f *GitHubFormatter) isTextFormatter() {}
+
+// stepSummary returns the Markdown summary of rr
+func (f *GitHubFormatter) stepSummary(rr *model.RunResult) string {
+	b := &strings.Builder{}
+	fmt.Fprint(b, "## spexec results\n\n")
+	fmt.Fprint(b, "| Spec | Examples | Failures | Skipped | Pending | Time |\n")
+	fmt.Fprint(b, "| --- | ---: | ---: | ---: | ---: | ---: |\n")
+	row := func(name string, summary model.SpecSummary, seconds string) {
+		fmt.Fprintf(b, "| %s | %d | %d | %d | %d | %ss |\n", name, summary.NumberOfTests, summary.NumberOfFailed, summary.NumberOfSkipped, summary.NumberOfPending, seconds)
+	}
+	for _, sr := range rr.SpecResults {
+		row(strings.ReplaceAll(util.RelativePath(sr.Name), "|", `\|`), sr.Summary, formatSeconds(sr.Duration))
+	}
+	row("**Total**", rr.Summary, formatSeconds(rr.Duration))
+
+	if len(f.failures) == 0 {
+		return b.String()
+	}
+
+	fmt.Fprint(b, "\n### Failures\n")
+	for _, failure := range f.failures {
+		location := failure.file
+		if failure.line != 0 {
+			location = fmt.Sprintf("%s:%d", location, failure.line)
+		}
+		fmt.Fprintf(b, "\n- **%s**", failure.tr.Name)
+		if len(location) != 0 {
+			fmt.Fprintf(b, " (`%s`)", location)
+		}
+		fmt.Fprintf(b, "\n\n  ```\n  %s\n  ```\n", strings.ReplaceAll(strings.Join(failureLines(failure.tr), "\n"), "\n", "\n  "))
+	}
+
+	return b.String()
+}
+
+// failureLines returns the failure messages of tr
+func failureLines(tr *model.TestResult) []string {
+	lines := make([]string, 0, len(tr.Messages)+len(tr.HookFailures))
+	for _, m := range tr.Messages {
+		lines = append(lines, fmt.Sprintf("%s: %s", m.Name, m.Message))
+	}
+	for _, m := range tr.HookFailures {
+		lines = append(lines, fmt.Sprintf("%s hook: %s", m.Name, m.Message))
+	}
+
+	return lines
+}
+
+// escapeGitHubData escapes the message of workflow command
+func escapeGitHubData(s string) string {
+	s = strings.ReplaceAll(s, "%", "%25")
+	s = strings.ReplaceAll(s, "\r", "%0D")
+	return strings.ReplaceAll(s, "\n", "%0A")
+}
+
+// escapeGitHubProperty escapes the property value of workflow command
+func escapeGitHubProperty(s string) string {
+	s = escapeGitHubData(s)
+	s = strings.ReplaceAll(s, ":", "%3A")
+	return strings.ReplaceAll(s, ",", "%2C")
+}
@@ -0,0 +1,66 @@
+package reporter
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/autopp/spexec/pkg/model"
+	g "github.com/onsi/ginkgo/v2" // Reporter are duplicated
+	. "github.com/onsi/gomega"
+)
+
+var _ = g.Describe("GitHubFormatter", func() {
+	g.It("writes groups, annotations and step summary", func() {
+		buf := &bytes.Buffer{}
+		w := newWriter(buf, false)
+		stepSummary := filepath.Join(g.GinkgoT().TempDir(), "summary.md")
+		f := &GitHubFormatter{StepSummary: stepSummary}
+
+		passed := &model.Test{Name: "passed", SpecFilename: "/tmp/spec.yaml", Line: 2}
+		failed := &model.Test{Name: "failed, 100%", SpecFilename: "/tmp/spec.yaml", Line: 5}
+		skipped := &model.Test{Name: "skipped", SpecFilename: "/tmp/spec.yaml", Line: 8}
+		trs := []*model.TestResult{
+			{Name: "passed", Status: model.TestPassed, IsSuccess: true, Duration: 120 * time.Millisecond},
+			{
+				Name:         "failed, 100%",
+				Status:       model.TestFailed,
+				Duration:     2 * time.Second,
+				Messages:     []*model.AssertionMessage{{Name: "status", Message: "should be 0, but got 1"}},
+				HookFailures: []*model.AssertionMessage{{Name: "afterEach", Message: "`false` failed"}},
+			},
+			{Name: "skipped", Status: model.TestSkipped, SkipReason: "not ready", IsSuccess: true},
+		}
+		sr := model.NewSpecResult("/tmp/spec.yaml", trs)
+		sr.Duration = 1500 * time.Millisecond
+
+		Expect(f.OnRunStart(w)).To(Succeed())
+		Expect(f.OnSpecStart(w, "/tmp/spec.yaml")).To(Succeed())
+		for i, t := range []*model.Test{passed, failed, skipped} {
+			Expect(f.OnTestComplete(w, t, trs[i])).To(Succeed())
+		}
+		Expect(f.OnSpecComplete(w, sr)).To(Succeed())
+		Expect(f.OnRunComplete(w, model.NewRunResult([]*model.SpecResult{sr}))).To(Succeed())
+
+		Expect(buf.String()).To(Equal(`::group::/tmp/spec.yaml
+PASS passed (0.120s)
+FAIL failed, 100% (2.000s)
+::error file=/tmp/spec.yaml,line=5,title=failed%2C 100%25::status: should be 0, but got 1%0AafterEach hook: ` + "`false`" + ` failed
+SKIP skipped (not ready)
+::endgroup::
+3 examples, 1 failures, 1 skipped
+`))
+
+		summary, err := os.ReadFile(stepSummary)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(summary)).To(Equal("## spexec results\n\n" +
+			"| Spec | Examples | Failures | Skipped | Pending | Time |\n" +
+			"| --- | ---: | ---: | ---: | ---: | ---: |\n" +
+			"| /tmp/spec.yaml | 3 | 1 | 1 | 0 | 1.500s |\n" +
+			"| **Total** | 3 | 1 | 1 | 0 | 1.500s |\n" +
+			"\n### Failures\n" +
+			"\n- **failed, 100%** (`/tmp/spec.yaml:5`)\n" +
+			"\n  ```\n  status: should be 0, but got 1\n  afterEach hook: `false` failed\n  ```\n"))
+	})
+})
@@ -24,46 +24,60 @@ import (
 /*
 JSONFormatter implements Reporter.
 
+It writes a JSON document of all specs after all test execution.
+
 Example of output:
 
 	{
-		"results": [
+		"specResults": [
 			{
-				"isSuccess": false,
-				"messages": [
+				"name": "spec.yaml",
+				"testResults": [
 					{
-						"name": "status",
-						"message": "expected success, but not"
-					}
-				]
+						"name": "test1",
+						"status": "failed",
+						"messages": [
+							{
+								"name": "status",
+								"message": "should succeed, but not"
+							}
+						],
+						"isSuccess": false,
+						"duration": 0.005,
+						...
+					},
+					...
+				],
+				"summary": {
+					"numberOfTests": 10,
+					"numberOfSucceeded": 6,
+					"numberOfFailed": 4,
+					"numberOfSkipped": 0,
+					"numberOfPending": 0,
+					"numberOfNotRun": 0
+				},
+				"duration": 0.012
 			},
 			...
 		],
 		"summary": {
-			"numberOfTests": 10,
-			"numberOfSuccess": 6,
-			"numberOfFailure": 4,
-		}
+			...
+		},
+		"duration": 0.034
 	}
 */
 type JSONFormatter struct{}
 
-type jsonSummary struct {
-	NumberOfTests   int `json:"numberOfTests"`
-	NumberOfSuccess int `json:"numberOfSuccess"`
-	NumberOfFailure int `json:"numberOfFailure"`
-}
-
-type jsonOutput struct {
-	Results []*model.TestResult `json:"results"`
-	Summary jsonSummary         `json:"summary"`
-}
-
 // OnRunStart is part of Reporter
 func (f *JSONFormatter) OnRunStart(w *Writer) error {
 	return nil
 }
 
+// OnSpecStart is part of Reporter
+func (f *JSONFormatter) OnSpecStart(w *Writer, name string) error {
+	return nil
+}
+
 // OnTestStart is part of Reporter
 func (f *JSONFormatter) OnTestStart(w *Writer, t *model.Test) error {
 	return nil
@@ -74,9 +88,14 @@ func (f *JSONFormatter) OnTestComplete(w *Writer, t *model.Test, tr *model.TestR
 	return nil
 }
 
+// OnSpecComplete is part of Reporter
+func (f *JSONFormatter) OnSpecComplete(w *Writer, sr *model.SpecResult) error {
+	return nil
+}
+
 // OnRunComplete is part of Reporter
-func (f *JSONFormatter) OnRunComplete(w *Writer, sr *model.SpecResult) error {
-	output, err := json.Marshal(sr)
+func (f *JSONFormatter) OnRunComplete(w *Writer, rr *model.RunResult) error {
+	output, err := json.Marshal(rr)
 
 	if err != nil {
 		return err
@@ -0,0 +1,191 @@
+// Copyright (C) 2021-2023	 Akira Tanimura (@autopp)
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reporter
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/autopp/spexec/pkg/model"
+)
+
+/*
+JUnitFormatter implements Reporter.
+
+Example of output:
+
+	<?xml version="1.0" encoding="UTF-8"?>
+	<testsuites tests="2" failures="1" errors="0" skipped="0" time="0.012">
+	  <testsuite name="spec.yaml" tests="2" failures="1" errors="0" skipped="0" time="0.012">
+	    <testcase name="test1" classname="spec.yaml" time="0.005"></testcase>
+	    <testcase name="test2" classname="spec.yaml" time="0.007">
+	      <failure message="status: should succeed, but not" type="failure">status: should succeed, but not</failure>
+	      <system-out>...</system-out>
+	    </testcase>
+	  </testsuite>
+	</testsuites>
+*/
+type JUnitFormatter struct{}
+
+type junitTestSuites struct {
+	XMLName    xml.Name          `xml:"testsuites"`
+	Tests      int               `xml:"tests,attr"`
+	Failures   int               `xml:"failures,attr"`
+	Errors     int               `xml:"errors,attr"`
+	Skipped    int               `xml:"skipped,attr"`
+	Time       string            `xml:"time,attr"`
+	TestSuites []*junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	XMLName    xml.Name         `xml:"testsuite"`
+	Name       string           `xml:"name,attr"`
+	Tests      int              `xml:"tests,attr"`
+	Failures   int              `xml:"failures,attr"`
+	Errors     int              `xml:"errors,attr"`
+	Skipped    int              `xml:"skipped,attr"`
+	Time       string           `xml:"time,attr"`
+	Properties *junitProperties `xml:"properties,omitempty"`
+	TestCases  []*junitTestCase `xml:"testcase"`
+}
+
+type junitProperties struct {
+	Properties []*junitProperty `xml:"property"`
+}
+
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Skipped   *junitSkipped `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+	SystemErr string        `xml:"system-err,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Body    string `xml:",chardata"`
+}
+
+type junitSkipped struct {
+	Message string `xml:"message,attr,omitempty"`
+}
+
+// OnRunStart is part of Reporter
+func (f *JUnitFormatter) OnRunStart(w *Writer) error {
+	return nil
+}
+
+// OnSpecStart is part of Reporter
+func (f *JUnitFormatter) OnSpecStart(w *Writer, name string) error {
+	return nil
+}
+
+// OnTestStart is part of Reporter
+func (f *JUnitFormatter) OnTestStart(w *Writer, t *model.Test) error {
+	return nil
+}
+
+// OnTestComplete is part of Reporter
+func (f *JUnitFormatter) OnTestComplete(w *Writer, t *model.Test, tr *model.TestResult) error {
+	return nil
+}
+
+// OnSpecComplete is part of Reporter
+func (f *JUnitFormatter) OnSpecComplete(w *Writer, sr *model.SpecResult) error {
+	return nil
+}
+
+func newJUnitTestSuites(rr *model.RunResult) *junitTestSuites {
+	suites := &junitTestSuites{
+		Tests:      rr.Summary.NumberOfTests,
+		Failures:   rr.Summary.NumberOfFailed,
+		Skipped:    rr.Summary.NumberOfSkipped + rr.Summary.NumberOfPending + rr.Summary.NumberOfNotRun,
+		Time:       formatJUnitTime(rr.Duration),
+		TestSuites: make([]*junitTestSuite, 0, len(rr.SpecResults)),
+	}
+	for _, sr := range rr.SpecResults {
+		suite := newJUnitTestSuite(sr)
+		if rr.Seed != nil {
+			suite.Properties = &junitProperties{Properties: []*junitProperty{{Name: "seed", Value: strconv.FormatInt(*rr.Seed, 10)}}}
+		}
+		suites.TestSuites = append(suites.TestSuites, suite)
+	}
+
+	return suites
+}
+
+func newJUnitTestSuite(sr *model.SpecResult) *junitTestSuite {
+	suite := &junitTestSuite{
+		Name:      sr.Name,
+		Tests:     sr.Summary.NumberOfTests,
+		Failures:  sr.Summary.NumberOfFailed,
+		Skipped:   sr.Summary.NumberOfSkipped + sr.Summary.NumberOfPending + sr.Summary.NumberOfNotRun,
+		Time:      formatJUnitTime(sr.Duration),
+		TestCases: make([]*junitTestCase, 0, len(sr.TestResults)),
+	}
+
+	for _, tr := range sr.TestResults {
+		tc := &junitTestCase{
+			Name:      tr.Name,
+			ClassName: sr.Name,
+			Time:      formatJUnitTime(tr.Duration),
+			SystemOut: string(tr.Stdout),
+			SystemErr: string(tr.Stderr),
+		}
+
+		switch {
+		case tr.Status == model.TestSkipped || tr.Status == model.TestPending || tr.Status == model.TestNotRun:
+			tc.Skipped = &junitSkipped{Message: tr.SkipReason}
+		case !tr.IsSuccess:
+			lines := failureLines(tr)
+			message := ""
+			if len(lines) != 0 {
+				message, _, _ = strings.Cut(lines[0], "\n")
+			}
+			tc.Failure = &junitFailure{Message: message, Type: "failure", Body: strings.Join(lines, "\n")}
+		}
+
+		suite.TestCases = append(suite.TestCases, tc)
+	}
+
+	return suite
+}
+
+func formatJUnitTime(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
+
+// OnRunComplete is part of Reporter
+func (f *JUnitFormatter) OnRunComplete(w *Writer, rr *model.RunResult) error {
+	output, err := xml.MarshalIndent(newJUnitTestSuites(rr), "", "  ")
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "%s%s\n", xml.Header, output)
+
+	return err
+}
@@ -0,0 +1,56 @@
+package reporter
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/autopp/spexec/pkg/model"
+	g "github.com/onsi/ginkgo/v2" // Reporter are duplicated
+	. "github.com/onsi/gomega"
+)
+
+var _ = g.Describe("JUnitFormatter", func() {
+	g.Describe("OnRunComplete()", func() {
+		g.It("writes testsuites with a testsuite for each spec", func() {
+			buf := &bytes.Buffer{}
+			sr1 := model.NewSpecResult("spec.yaml", []*model.TestResult{
+				{Name: "passed", Status: model.TestPassed, IsSuccess: true, Duration: 1500 * time.Millisecond, Stdout: []byte("hello\n")},
+				{
+					Name:         "failed",
+					Status:       model.TestFailed,
+					Messages:     []*model.AssertionMessage{{Name: "status", Message: "should be 0, but got 1"}},
+					HookFailures: []*model.AssertionMessage{{Name: "afterEach", Message: "`false` failed"}},
+					Duration:     500 * time.Millisecond,
+					Stderr:       []byte("oops <&>\n"),
+				},
+				{Name: "skipped", Status: model.TestSkipped, SkipReason: "not ready", IsSuccess: true},
+			})
+			sr1.Duration = 2 * time.Second
+			sr2 := model.NewSpecResult("other.yaml", []*model.TestResult{
+				{Name: "passed", Status: model.TestPassed, IsSuccess: true, Duration: 250 * time.Millisecond},
+			})
+			sr2.Duration = 250 * time.Millisecond
+
+			Expect((&JUnitFormatter{}).OnRunComplete(newWriter(buf, false), model.NewRunResult([]*model.SpecResult{sr1, sr2}))).To(Succeed())
+			Expect(buf.String()).To(Equal(`<?xml version="1.0" encoding="UTF-8"?>
+<testsuites tests="4" failures="1" errors="0" skipped="1" time="2.250">
+  <testsuite name="spec.yaml" tests="3" failures="1" errors="0" skipped="1" time="2.000">
+    <testcase name="passed" classname="spec.yaml" time="1.500">
+      <system-out>hello&#xA;</system-out>
+    </testcase>
+    <testcase name="failed" classname="spec.yaml" time="0.500">
+      <failure message="status: should be 0, but got 1" type="failure">status: should be 0, but got 1&#xA;afterEach hook: ` + "`false`" + ` failed</failure>
+      <system-err>oops &lt;&amp;&gt;&#xA;</system-err>
+    </testcase>
+    <testcase name="skipped" classname="spec.yaml" time="0.000">
+      <skipped message="not ready"></skipped>
+    </testcase>
+  </testsuite>
+  <testsuite name="other.yaml" tests="1" failures="0" errors="0" skipped="0" time="0.250">
+    <testcase name="passed" classname="other.yaml" time="0.250"></testcase>
+  </testsuite>
+</testsuites>
+`))
+		})
+	})
+})
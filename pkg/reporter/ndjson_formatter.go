@@ -0,0 +1,100 @@
+// Copyright (C) 2021-2023	 Akira Tanimura (@autopp)
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reporter
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/autopp/spexec/pkg/model"
+)
+
+// NDJSONSchemaVersion is the version of the event schema written by NDJSONFormatter.
+// It is incremented when a field is removed or its meaning is changed.
+const NDJSONSchemaVersion = 1
+
+/*
+NDJSONFormatter implements Reporter.
+
+It writes one JSON event per line as soon as it happens. See docs/ndjson.md for the schema of events.
+
+Example of output:
+
+	{"event":"runStart","schemaVersion":1}
+	{"event":"specStart","spec":"spec.yaml"}
+	{"event":"testStart","spec":"spec.yaml","name":"test1"}
+	{"event":"testComplete","spec":"spec.yaml","name":"test1","result":{...},"duration":0.012}
+	{"event":"specComplete","spec":"spec.yaml","summary":{...}}
+	{"event":"runComplete","summary":{...},"success":true}
+*/
+type NDJSONFormatter struct {
+	spec string
+}
+
+type ndjsonEvent struct {
+	Event         string             `json:"event"`
+	SchemaVersion int                `json:"schemaVersion,omitempty"`
+	Spec          string             `json:"spec,omitempty"`
+	Name          string             `json:"name,omitempty"`
+	Groups        []string           `json:"groups,omitempty"`
+	Result        *model.TestResult  `json:"result,omitempty"`
+	Duration      *float64           `json:"duration,omitempty"`
+	Summary       *model.SpecSummary `json:"summary,omitempty"`
+	Success       *bool              `json:"success,omitempty"`
+	Seed          *int64             `json:"seed,omitempty"`
+}
+
+func (f *NDJSONFormatter) emit(w *Writer, e *ndjsonEvent) error {
+	output, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "%s\n", output)
+	return err
+}
+
+// OnRunStart is part of Reporter
+func (f *NDJSONFormatter) OnRunStart(w *Writer) error {
+	return f.emit(w, &ndjsonEvent{Event: "runStart", SchemaVersion: NDJSONSchemaVersion})
+}
+
+// OnSpecStart is part of Reporter
+func (f *NDJSONFormatter) OnSpecStart(w *Writer, name string) error {
+	f.spec = name
+	return f.emit(w, &ndjsonEvent{Event: "specStart", Spec: name})
+}
+
+// OnTestStart is part of Reporter
+func (f *NDJSONFormatter) OnTestStart(w *Writer, t *model.Test) error {
+	return f.emit(w, &ndjsonEvent{Event: "testStart", Spec: f.spec, Name: t.GetName(), Groups: t.Group.GetNames()})
+}
+
+// OnTestComplete is part of Reporter
+func (f *NDJSONFormatter) OnTestComplete(w *Writer, t *model.Test, tr *model.TestResult) error {
+	duration := tr.Duration.Seconds()
+	return f.emit(w, &ndjsonEvent{Event: "testComplete", Spec: f.spec, Name: tr.Name, Groups: tr.Groups, Result: tr, Duration: &duration})
+}
+
+// OnSpecComplete is part of Reporter
+func (f *NDJSONFormatter) OnSpecComplete(w *Writer, sr *model.SpecResult) error {
+	return f.emit(w, &ndjsonEvent{Event: "specComplete", Spec: sr.Name, Summary: &sr.Summary})
+}
+
+// OnRunComplete is part of Reporter
+func (f *NDJSONFormatter) OnRunComplete(w *Writer, rr *model.RunResult) error {
+	success := rr.IsSuccess()
+	return f.emit(w, &ndjsonEvent{Event: "runComplete", Summary: &rr.Summary, Success: &success, Seed: rr.Seed})
+}
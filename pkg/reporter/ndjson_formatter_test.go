@@ -0,0 +1,37 @@
+package reporter
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/autopp/spexec/pkg/model"
+	g "github.com/onsi/ginkgo/v2" // Reporter are duplicated
+	. "github.com/onsi/gomega"
+)
+
+var _ = g.Describe("NDJSONFormatter", func() {
+	g.It("writes one event per line", func() {
+		buf := &bytes.Buffer{}
+		w := newWriter(buf, false)
+		f := &NDJSONFormatter{}
+		t := &model.Test{Name: "test1", Group: &model.Group{Name: "group"}}
+		tr := &model.TestResult{Name: "group test1", Groups: []string{"group"}, Status: model.TestPassed, Messages: []*model.AssertionMessage{}, IsSuccess: true, Duration: 1500 * time.Millisecond}
+		sr := model.NewSpecResult("spec.yaml", []*model.TestResult{tr})
+
+		Expect(f.OnRunStart(w)).To(Succeed())
+		Expect(f.OnSpecStart(w, "spec.yaml")).To(Succeed())
+		Expect(f.OnTestStart(w, t)).To(Succeed())
+		Expect(f.OnTestComplete(w, t, tr)).To(Succeed())
+		Expect(f.OnSpecComplete(w, sr)).To(Succeed())
+		Expect(f.OnRunComplete(w, model.NewRunResult([]*model.SpecResult{sr}))).To(Succeed())
+
+		summary := `{"numberOfTests":1,"numberOfSucceeded":1,"numberOfFailed":0,"numberOfSkipped":0,"numberOfPending":0,"numberOfNotRun":0}`
+		Expect(buf.String()).To(Equal(`{"event":"runStart","schemaVersion":1}
+{"event":"specStart","spec":"spec.yaml"}
+{"event":"testStart","spec":"spec.yaml","name":"group test1","groups":["group"]}
+{"event":"testComplete","spec":"spec.yaml","name":"group test1","groups":["group"],"result":{"name":"group test1","groups":["group"],"status":"passed","messages":[],"isSuccess":true,"duration":1.5,"userTime":0,"systemTime":0},"duration":1.5}
+{"event":"specComplete","spec":"spec.yaml","summary":` + summary + `}
+{"event":"runComplete","summary":` + summary + `,"success":true}
+`))
+	})
+})
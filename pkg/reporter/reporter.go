@@ -22,23 +22,54 @@ import (
 )
 
 // Reporter provides formatted test reporter
+//
+// Callbacks are fanned out to all formatters of the targets.
 type Reporter struct {
+	targets []*target
+	profile int
+}
+
+type target struct {
 	w  *Writer
 	rf ReportFormatter
 }
 
 type Config struct {
-	colorMode bool
-	w         io.Writer
-	rf        ReportFormatter
+	colorMode  bool
+	w          io.Writer
+	rf         ReportFormatter
+	targets    []*target
+	profile    int
+	showOutput ShowOutput
 }
 
+// ShowOutput specifies the tests whose stdout and stderr are printed by the formatters for human.
+// Results of tests keep them regardless of it.
+type ShowOutput int
+
+const (
+	ShowOutputFailures ShowOutput = iota
+	ShowOutputNever
+	ShowOutputAlways
+)
+
 // ReportFormatter is the interface implemented by report formatter
+//
+// A run consists of one or more specs, and callbacks are called in the following order:
+//
+//	OnRunStart
+//	  OnSpecStart
+//	    OnTestStart, OnTestComplete (for each test of the spec)
+//	  OnSpecComplete
+//	  ... (for each spec)
+//	OnRunComplete
 type ReportFormatter interface {
 	OnRunStart(w *Writer) error
+	OnSpecStart(w *Writer, name string) error
 	OnTestStart(w *Writer, t *model.Test) error
 	OnTestComplete(w *Writer, t *model.Test, tr *model.TestResult) error
-	OnRunComplete(w *Writer, sr *model.SpecResult) error
+	OnSpecComplete(w *Writer, sr *model.SpecResult) error
+	OnRunComplete(w *Writer, rr *model.RunResult) error
 }
 
 // Option is functional option of New
@@ -66,6 +97,32 @@ func WithFormatter(formatter ReportFormatter) Option {
 	}
 }
 
+// WithTarget is a option of New to add a formatter which writes to w
+//
+// It can be given multiple times. When it is given, WithWriter, WithColor and WithFormatter are ignored.
+func WithTarget(rf ReportFormatter, w io.Writer, colorMode bool) Option {
+	return func(c *Config) error {
+		c.targets = append(c.targets, &target{w: newWriter(w, colorMode), rf: rf})
+		return nil
+	}
+}
+
+// WithProfile is a option of New to print the n slowest tests after all test execution
+func WithProfile(n int) Option {
+	return func(c *Config) error {
+		c.profile = n
+		return nil
+	}
+}
+
+// WithShowOutput is a option of New to specify the tests whose stdout and stderr are printed by the formatters for human
+func WithShowOutput(showOutput ShowOutput) Option {
+	return func(c *Config) error {
+		c.showOutput = showOutput
+		return nil
+	}
+}
+
 // New returns a new Reporter
 func New(opts ...Option) (*Reporter, error) {
 	r := &Reporter{}
@@ -80,28 +137,79 @@ func New(opts ...Option) (*Reporter, error) {
 			return nil, err
 		}
 	}
-	r.w = newWriter(c.w, c.colorMode)
-	r.rf = c.rf
+	r.targets = c.targets
+	if len(r.targets) == 0 {
+		r.targets = []*target{{w: newWriter(c.w, c.colorMode), rf: c.rf}}
+	}
+	for _, t := range r.targets {
+		t.w.showOutput = c.showOutput
+	}
+	r.profile = c.profile
 
 	return r, nil
 }
 
+func (r *Reporter) each(f func(w *Writer, rf ReportFormatter) error) error {
+	for _, t := range r.targets {
+		if err := f(t.w, t.rf); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // OnRunStart should be called before all test execution
 func (r *Reporter) OnRunStart() error {
-	return r.rf.OnRunStart(r.w)
+	return r.each(func(w *Writer, rf ReportFormatter) error {
+		return rf.OnRunStart(w)
+	})
+}
+
+// OnSpecStart should be called before test execution of each spec
+func (r *Reporter) OnSpecStart(name string) error {
+	return r.each(func(w *Writer, rf ReportFormatter) error {
+		return rf.OnSpecStart(w, name)
+	})
 }
 
 // OnTestStart should be called before each test execution
 func (r *Reporter) OnTestStart(t *model.Test) error {
-	return r.rf.OnTestStart(r.w, t)
+	return r.each(func(w *Writer, rf ReportFormatter) error {
+		return rf.OnTestStart(w, t)
+	})
 }
 
 // OnTestComplete should be called after each test execution
 func (r *Reporter) OnTestComplete(t *model.Test, tr *model.TestResult) error {
-	return r.rf.OnTestComplete(r.w, t, tr)
+	return r.each(func(w *Writer, rf ReportFormatter) error {
+		return rf.OnTestComplete(w, t, tr)
+	})
+}
+
+// OnSpecComplete should be called after test execution of each spec
+func (r *Reporter) OnSpecComplete(sr *model.SpecResult) error {
+	return r.each(func(w *Writer, rf ReportFormatter) error {
+		return rf.OnSpecComplete(w, sr)
+	})
 }
 
 // OnRunComplete should be called afterall test execution
-func (r *Reporter) OnRunComplete(sr *model.SpecResult) error {
-	return r.rf.OnRunComplete(r.w, sr)
+//
+// The slowest tests and the seed of random order are printed by the formatters for human.
+func (r *Reporter) OnRunComplete(rr *model.RunResult) error {
+	return r.each(func(w *Writer, rf ReportFormatter) error {
+		if err := rf.OnRunComplete(w, rr); err != nil {
+			return err
+		}
+
+		_, ok := rf.(textFormatter)
+		if ok && r.profile > 0 {
+			printProfile(w, rr, r.profile)
+		}
+		if ok && rr.Seed != nil {
+			printSeed(w, *rr.Seed)
+		}
+		return nil
+	})
 }
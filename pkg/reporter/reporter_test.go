@@ -2,6 +2,7 @@ package reporter
 
 import (
 	"bytes"
+	"time"
 
 	"github.com/autopp/spexec/pkg/model"
 	g "github.com/onsi/ginkgo/v2" // Reporter are duplicated
@@ -10,8 +11,10 @@ import (
 
 type testReportFormatter struct {
 	OnRunStartCalled     int
+	OnSpecStartCalled    int
 	OnTestStartCalled    int
 	OnTestCompleteCalled int
+	OnSpecCompleteCalled int
 	OnRunCompleteCalled  int
 }
 
@@ -20,6 +23,11 @@ func (rf *testReportFormatter) OnRunStart(w *Writer) error {
 	return nil
 }
 
+func (rf *testReportFormatter) OnSpecStart(w *Writer, name string) error {
+	rf.OnSpecStartCalled++
+	return nil
+}
+
 func (rf *testReportFormatter) OnTestStart(w *Writer, t *model.Test) error {
 	rf.OnTestStartCalled++
 	return nil
@@ -30,11 +38,22 @@ func (rf *testReportFormatter) OnTestComplete(w *Writer, t *model.Test, tr *mode
 	return nil
 }
 
-func (rf *testReportFormatter) OnRunComplete(w *Writer, sr *model.SpecResult) error {
+func (rf *testReportFormatter) OnSpecComplete(w *Writer, sr *model.SpecResult) error {
+	rf.OnSpecCompleteCalled++
+	return nil
+}
+
+func (rf *testReportFormatter) OnRunComplete(w *Writer, rr *model.RunResult) error {
 	rf.OnRunCompleteCalled++
 	return nil
 }
 
+type testTextReportFormatter struct {
+	testReportFormatter
+}
+
+func (rf *testTextReportFormatter) isTextFormatter() {}
+
 var _ = g.Describe("Rerporter", func() {
 	var rf *testReportFormatter
 	var r *Reporter
@@ -49,6 +68,21 @@ var _ = g.Describe("Rerporter", func() {
 			r.OnRunStart()
 			Expect(rf.OnRunStartCalled).To(Equal(1))
 		})
+
+		g.It("calls OnRunStart() of all formatters when WithTarget is given", func() {
+			other := new(testReportFormatter)
+			r, _ = New(WithFormatter(new(testReportFormatter)), WithTarget(rf, &bytes.Buffer{}, false), WithTarget(other, &bytes.Buffer{}, true))
+			r.OnRunStart()
+			Expect(rf.OnRunStartCalled).To(Equal(1))
+			Expect(other.OnRunStartCalled).To(Equal(1))
+		})
+	})
+
+	g.Describe("OnSpecStart()", func() {
+		g.It("calls OnSpecStart() of formatter", func() {
+			r.OnSpecStart("spec.yaml")
+			Expect(rf.OnSpecStartCalled).To(Equal(1))
+		})
 	})
 
 	g.Describe("OnTestStart()", func() {
@@ -60,9 +94,38 @@ var _ = g.Describe("Rerporter", func() {
 
 	g.Describe("OnTestComplete()", func() {
 		g.It("calls OnTestComplete() of formatter", func() {
-			r.OnTestComplete(nil, nil)
+			r.OnTestComplete(nil, &model.TestResult{})
 			Expect(rf.OnTestCompleteCalled).To(Equal(1))
 		})
+
+		g.DescribeTable("keeps streams of test results and prints them only for tests which should be reported",
+			func(showOutput ShowOutput, isSuccess bool, printed bool) {
+				buf := &bytes.Buffer{}
+				r, _ = New(WithTarget(&DocumentationFormatter{}, buf, false), WithShowOutput(showOutput))
+				t := &model.Test{Name: "test", Group: &model.Group{}}
+				tr := &model.TestResult{IsSuccess: isSuccess, Stdout: []byte("out"), Stderr: []byte("err")}
+				r.OnTestComplete(t, tr)
+				r.OnRunComplete(model.NewRunResult([]*model.SpecResult{model.NewSpecResult("spec.yaml", []*model.TestResult{tr})}))
+				Expect(tr.Stdout).To(Equal([]byte("out")))
+				Expect(tr.Stderr).To(Equal([]byte("err")))
+				if printed {
+					Expect(buf.String()).To(ContainSubstring("stdout:\n"))
+				} else {
+					Expect(buf.String()).NotTo(ContainSubstring("stdout:\n"))
+				}
+			},
+			g.Entry("never with failure", ShowOutputNever, false, false),
+			g.Entry("failures with success", ShowOutputFailures, true, false),
+			g.Entry("failures with failure", ShowOutputFailures, false, true),
+			g.Entry("always with success", ShowOutputAlways, true, true),
+		)
+	})
+
+	g.Describe("OnSpecComplete()", func() {
+		g.It("calls OnSpecComplete() of formatter", func() {
+			r.OnSpecComplete(nil)
+			Expect(rf.OnSpecCompleteCalled).To(Equal(1))
+		})
 	})
 
 	g.Describe("OnRunComplete()", func() {
@@ -70,5 +133,41 @@ var _ = g.Describe("Rerporter", func() {
 			r.OnRunComplete(nil)
 			Expect(rf.OnRunCompleteCalled).To(Equal(1))
 		})
+
+		g.It("prints the slowest tests to formatters for human when WithProfile is given", func() {
+			buf := &bytes.Buffer{}
+			otherBuf := &bytes.Buffer{}
+			r, _ = New(WithTarget(&testTextReportFormatter{}, buf, false), WithTarget(rf, otherBuf, false), WithProfile(2))
+			rr := model.NewRunResult([]*model.SpecResult{
+				model.NewSpecResult("spec.yaml", []*model.TestResult{
+					{Name: "fast", Status: model.TestPassed, IsSuccess: true, Duration: 100 * time.Millisecond},
+					{Name: "slow", Status: model.TestPassed, IsSuccess: true, Duration: 300 * time.Millisecond},
+					{Name: "slower", Status: model.TestFailed, IsSuccess: false, Duration: 600 * time.Millisecond},
+				}),
+			})
+
+			Expect(r.OnRunComplete(rr)).To(Succeed())
+			Expect(buf.String()).To(Equal(`
+Top 2 slowest tests (0.900 seconds, 90.0% of total test time):
+  slower
+    0.600 seconds spec.yaml
+  slow
+    0.300 seconds spec.yaml
+`))
+			Expect(otherBuf.String()).To(BeEmpty())
+		})
+
+		g.It("prints the seed to formatters for human when tests are randomized", func() {
+			buf := &bytes.Buffer{}
+			otherBuf := &bytes.Buffer{}
+			r, _ = New(WithTarget(&testTextReportFormatter{}, buf, false), WithTarget(rf, otherBuf, false))
+			rr := model.NewRunResult([]*model.SpecResult{})
+			seed := int64(1234)
+			rr.Seed = &seed
+
+			Expect(r.OnRunComplete(rr)).To(Succeed())
+			Expect(buf.String()).To(Equal("\nRandomized with seed 1234\n"))
+			Expect(otherBuf.String()).To(BeEmpty())
+		})
 	})
 })
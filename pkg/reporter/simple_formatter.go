@@ -25,8 +25,9 @@ SimpleFormatter implements Reporter.
 
 Example of output:
 
-	.F.
-	3 examples, 1 failures
+	.F*
+	Finished in 0.012 seconds
+	3 examples, 1 failures, 1 skipped
 */
 type SimpleFormatter struct{}
 
@@ -35,6 +36,11 @@ func (f *SimpleFormatter) OnRunStart(w *Writer) error {
 	return nil
 }
 
+// OnSpecStart is part of Reporter
+func (f *SimpleFormatter) OnSpecStart(w *Writer, name string) error {
+	return nil
+}
+
 // OnTestStart is part of Reporter
 func (f *SimpleFormatter) OnTestStart(w *Writer, t *model.Test) error {
 	return nil
@@ -42,7 +48,11 @@ func (f *SimpleFormatter) OnTestStart(w *Writer, t *model.Test) error {
 
 // OnTestComplete is part of Reporter
 func (f *SimpleFormatter) OnTestComplete(w *Writer, t *model.Test, tr *model.TestResult) error {
-	if tr.IsSuccess {
+	if tr.Status == model.TestSkipped || tr.Status == model.TestPending {
+		w.UseColor(Yellow, func() {
+			fmt.Fprint(w, "*")
+		})
+	} else if tr.IsSuccess {
 		w.UseColor(Green, func() {
 			fmt.Fprint(w, ".")
 		})
@@ -54,9 +64,18 @@ func (f *SimpleFormatter) OnTestComplete(w *Writer, t *model.Test, tr *model.Tes
 	return nil
 }
 
+// OnSpecComplete is part of Reporter
+func (f *SimpleFormatter) OnSpecComplete(w *Writer, sr *model.SpecResult) error {
+	return nil
+}
+
 // OnRunComplete is part of Reporter
-func (f *SimpleFormatter) OnRunComplete(w *Writer, sr *model.SpecResult) error {
-	printFailures(w, sr.GetFailedTestResults())
-	fmt.Fprintf(w, "\n%d examples, %d failures\n", sr.Summary.NumberOfTests, sr.Summary.NumberOfFailed)
+func (f *SimpleFormatter) OnRunComplete(w *Writer, rr *model.RunResult) error {
+	printOutputs(w, rr.GetSucceededTestResults())
+	printFailures(w, rr.GetFailedTestResults())
+	fmt.Fprintf(w, "\nFinished in %s seconds\n", formatSeconds(rr.Duration))
+	fmt.Fprintf(w, "%d examples, %d failures%s\n", rr.Summary.NumberOfTests, rr.Summary.NumberOfFailed, formatNotRun(rr.Summary))
 	return nil
 }
+
+func (f *SimpleFormatter) isTextFormatter() {}
@@ -0,0 +1,128 @@
+// Copyright (C) 2021-2023	 Akira Tanimura (@autopp)
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reporter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/autopp/spexec/pkg/model"
+	"gopkg.in/yaml.v3"
+)
+
+/*
+TAPFormatter implements Reporter.
+
+Example of output:
+
+	TAP version 13
+	ok 1 - test1
+	  ---
+	  duration_ms: 12.345
+	  ...
+	not ok 2 - test2
+	  ---
+	  duration_ms: 6.789
+	  messages:
+	    - name: status
+	      message: should succeed, but not
+	  ...
+	ok 3 - test3 # SKIP not ready
+	1..3
+*/
+type TAPFormatter struct {
+	count int
+}
+
+type tapDiagnostic struct {
+	DurationMS   float64                   `yaml:"duration_ms"`
+	Messages     []*model.AssertionMessage `yaml:"messages,omitempty"`
+	HookFailures []*model.AssertionMessage `yaml:"hookFailures,omitempty"`
+}
+
+// OnRunStart is part of Reporter
+func (f *TAPFormatter) OnRunStart(w *Writer) error {
+	f.count = 0
+	_, err := fmt.Fprintln(w, "TAP version 13")
+	return err
+}
+
+// OnSpecStart is part of Reporter
+func (f *TAPFormatter) OnSpecStart(w *Writer, name string) error {
+	return nil
+}
+
+// OnTestStart is part of Reporter
+func (f *TAPFormatter) OnTestStart(w *Writer, t *model.Test) error {
+	return nil
+}
+
+// OnTestComplete is part of Reporter
+func (f *TAPFormatter) OnTestComplete(w *Writer, t *model.Test, tr *model.TestResult) error {
+	f.count++
+	description := escapeTAPDescription(tr.Name)
+
+	switch {
+	case tr.Status == model.TestSkipped:
+		_, err := fmt.Fprintf(w, "ok %d - %s # SKIP %s\n", f.count, description, tr.SkipReason)
+		return err
+	case tr.Status == model.TestPending:
+		_, err := fmt.Fprintf(w, "ok %d - %s # SKIP pending: %s\n", f.count, description, tr.SkipReason)
+		return err
+	}
+
+	result := "ok"
+	if !tr.IsSuccess {
+		result = "not ok"
+	}
+	if _, err := fmt.Fprintf(w, "%s %d - %s\n", result, f.count, description); err != nil {
+		return err
+	}
+
+	diagnostic := &strings.Builder{}
+	e := yaml.NewEncoder(diagnostic)
+	e.SetIndent(2)
+	durationMS := float64(tr.Duration.Microseconds()) / 1000
+	if err := e.Encode(&tapDiagnostic{DurationMS: durationMS, Messages: tr.Messages, HookFailures: tr.HookFailures}); err != nil {
+		return err
+	}
+
+	lines := strings.Split(strings.TrimSuffix(diagnostic.String(), "\n"), "\n")
+	_, err := fmt.Fprintf(w, "  ---\n  %s\n  ...\n", strings.Join(lines, "\n  "))
+	return err
+}
+
+// OnSpecComplete is part of Reporter
+func (f *TAPFormatter) OnSpecComplete(w *Writer, sr *model.SpecResult) error {
+	return nil
+}
+
+// OnRunComplete is part of Reporter
+func (f *TAPFormatter) OnRunComplete(w *Writer, rr *model.RunResult) error {
+	if rr.Seed != nil {
+		if _, err := fmt.Fprintf(w, "# Randomized with seed %d\n", *rr.Seed); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, "1..%d\n", f.count)
+	return err
+}
+
+// escapeTAPDescription escapes characters which have special meaning in the description of test line
+func escapeTAPDescription(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, "#", `\#`)
+	return strings.ReplaceAll(s, "\n", " ")
+}
@@ -0,0 +1,66 @@
+package reporter
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/autopp/spexec/pkg/model"
+	g "github.com/onsi/ginkgo/v2" // Reporter are duplicated
+	. "github.com/onsi/gomega"
+)
+
+var _ = g.Describe("TAPFormatter", func() {
+	g.It("streams test lines and writes plan at the end", func() {
+		buf := &bytes.Buffer{}
+		w := newWriter(buf, false)
+		f := &TAPFormatter{}
+		trs := []*model.TestResult{
+			{Name: "passed #1", Status: model.TestPassed, IsSuccess: true, Duration: 1500 * time.Microsecond},
+			{
+				Name:     "failed",
+				Status:   model.TestFailed,
+				Duration: 2 * time.Second,
+				Messages: []*model.AssertionMessage{{Name: "status", Message: "should be 0, but got 1"}},
+			},
+			{Name: "skipped", Status: model.TestSkipped, SkipReason: "not ready", IsSuccess: true},
+			{Name: "pending", Status: model.TestPending, SkipReason: "No reason given", IsSuccess: true},
+		}
+
+		Expect(f.OnRunStart(w)).To(Succeed())
+		for _, tr := range trs {
+			Expect(f.OnTestComplete(w, nil, tr)).To(Succeed())
+		}
+		Expect(f.OnRunComplete(w, model.NewRunResult([]*model.SpecResult{model.NewSpecResult("spec.yaml", trs)}))).To(Succeed())
+
+		Expect(buf.String()).To(Equal(`TAP version 13
+ok 1 - passed \#1
+  ---
+  duration_ms: 1.5
+  ...
+not ok 2 - failed
+  ---
+  duration_ms: 2000
+  messages:
+    - name: status
+      message: should be 0, but got 1
+  ...
+ok 3 - skipped # SKIP not ready
+ok 4 - pending # SKIP pending: No reason given
+1..4
+`))
+	})
+
+	g.It("writes the seed as a comment when tests are randomized", func() {
+		buf := &bytes.Buffer{}
+		w := newWriter(buf, false)
+		f := &TAPFormatter{}
+		rr := model.NewRunResult([]*model.SpecResult{})
+		seed := int64(1234)
+		rr.Seed = &seed
+
+		Expect(f.OnRunStart(w)).To(Succeed())
+		Expect(f.OnRunComplete(w, rr)).To(Succeed())
+
+		Expect(buf.String()).To(Equal("TAP version 13\n# Randomized with seed 1234\n1..0\n"))
+	})
+})
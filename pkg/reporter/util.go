@@ -16,6 +16,8 @@ package reporter
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/autopp/spexec/pkg/model"
 )
@@ -27,9 +29,131 @@ func printFailures(w *Writer, failures []*model.TestResult) {
 
 	fmt.Fprintln(w, "\nFailures:")
 	for i, tr := range failures {
-		fmt.Fprintf(w, "\n  %d) %s\n", i+1, tr.Name)
+		fmt.Fprintf(w, "\n  %d) %s%s\n", i+1, tr.Name, formatTestTime(tr))
 		for _, m := range tr.Messages {
 			fmt.Fprintf(w, "    %s: %s\n", m.Name, m.Message)
 		}
+		for _, m := range tr.HookFailures {
+			fmt.Fprintf(w, "    %s hook: %s\n", m.Name, strings.ReplaceAll(m.Message, "\n", "\n      "))
+		}
+		printOutput(w, tr, 2)
+	}
+}
+
+// printOutputs prints stdout and stderr of the given tests which have them
+func printOutputs(w *Writer, results []*model.TestResult) {
+	printed := 0
+	for _, tr := range results {
+		if (len(tr.Stdout) == 0 && len(tr.Stderr) == 0) || !showsOutput(w, tr) {
+			continue
+		}
+		if printed == 0 {
+			fmt.Fprintln(w, "\nOutputs:")
+		}
+		printed++
+		fmt.Fprintf(w, "\n  %d) %s%s\n", printed, tr.Name, formatTestTime(tr))
+		printOutput(w, tr, 2)
+	}
+}
+
+// maxOutputLines is the max number of lines printed for each of stdout and stderr
+const maxOutputLines = 20
+
+// showsOutput returns whether stdout and stderr of tr should be printed to w
+func showsOutput(w *Writer, tr *model.TestResult) bool {
+	switch w.showOutput {
+	case ShowOutputNever:
+		return false
+	case ShowOutputFailures:
+		return !tr.IsSuccess
+	default:
+		return true
+	}
+}
+
+// printOutput prints stdout and stderr of tr with indentation, omitting the leading lines of long output.
+// Nothing is printed when they should not be shown.
+func printOutput(w *Writer, tr *model.TestResult, depth int) {
+	if !showsOutput(w, tr) {
+		return
+	}
+
+	for _, stream := range []struct {
+		name   string
+		output []byte
+	}{{"stdout", tr.Stdout}, {"stderr", tr.Stderr}} {
+		if len(stream.output) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(w, "%s%s:\n", indent(depth), stream.name)
+		lines := strings.Split(strings.TrimSuffix(string(stream.output), "\n"), "\n")
+		if omitted := len(lines) - maxOutputLines; omitted > 0 {
+			fmt.Fprintf(w, "%s... (%d lines omitted)\n", indent(depth+1), omitted)
+			lines = lines[omitted:]
+		}
+		for _, line := range lines {
+			fmt.Fprintf(w, "%s%s\n", indent(depth+1), line)
+		}
+	}
+}
+
+// formatNotRun returns the counts of skipped, pending and not run tests for summary line, omitting zero counts
+func formatNotRun(summary model.SpecSummary) string {
+	s := ""
+	if summary.NumberOfSkipped != 0 {
+		s += fmt.Sprintf(", %d skipped", summary.NumberOfSkipped)
+	}
+	if summary.NumberOfPending != 0 {
+		s += fmt.Sprintf(", %d pending", summary.NumberOfPending)
+	}
+	if summary.NumberOfNotRun != 0 {
+		s += fmt.Sprintf(", %d not run", summary.NumberOfNotRun)
+	}
+
+	return s
+}
+
+// textFormatter is implemented by the formatters whose output is for human
+type textFormatter interface {
+	isTextFormatter()
+}
+
+// formatSeconds returns d in seconds with millisecond precision
+func formatSeconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
+
+// formatTestTime returns the time taken by tr to append to its name
+func formatTestTime(tr *model.TestResult) string {
+	return fmt.Sprintf(" (%ss)", formatSeconds(tr.Duration))
+}
+
+// printSeed prints the seed to reproduce the random order
+func printSeed(w *Writer, seed int64) {
+	fmt.Fprintf(w, "\nRandomized with seed %d\n", seed)
+}
+
+// printProfile prints the n slowest tests in rr
+func printProfile(w *Writer, rr *model.RunResult, n int) {
+	var total, slowest time.Duration
+	for _, sr := range rr.SpecResults {
+		for _, tr := range sr.TestResults {
+			total += tr.Duration
+		}
+	}
+	results := rr.SlowestTestResults(n)
+	for _, r := range results {
+		slowest += r.Duration
+	}
+
+	percentage := 0.0
+	if total > 0 {
+		percentage = float64(slowest) / float64(total) * 100
+	}
+	fmt.Fprintf(w, "\nTop %d slowest tests (%s seconds, %.1f%% of total test time):\n", len(results), formatSeconds(slowest), percentage)
+	for _, r := range results {
+		fmt.Fprintf(w, "  %s\n", r.Name)
+		fmt.Fprintf(w, "    %s seconds %s\n", formatSeconds(r.Duration), r.SpecName)
 	}
 }
@@ -44,10 +44,12 @@ type Writer struct {
 	io.Writer
 	colorMode  bool
 	colorStack []Color
+	// showOutput specifies the tests whose stdout and stderr are printed by the formatters for human
+	showOutput ShowOutput
 }
 
 func newWriter(w io.Writer, colorMode bool) *Writer {
-	return &Writer{w, colorMode, []Color{Reset}}
+	return &Writer{Writer: w, colorMode: colorMode, colorStack: []Color{Reset}}
 }
 
 func (w *Writer) UseColor(c Color, f func()) {
@@ -15,41 +15,485 @@
 package runner
 
 import (
+	"fmt"
+	"sync"
+	"time"
+
 	"github.com/autopp/spexec/pkg/model"
 	"github.com/autopp/spexec/pkg/reporter"
 )
 
-type Runner struct{}
+type Runner struct {
+	jobs            int
+	unorderedReport bool
+	maxFailures     int
+	// failures is the number of failed tests in the specs run by r
+	failures int
+}
+
+// Option is functional option of NewRunner
+type Option func(r *Runner)
+
+// WithJobs is a option of NewRunner to specify the number of tests run concurrently
+func WithJobs(jobs int) Option {
+	return func(r *Runner) {
+		if jobs > 0 {
+			r.jobs = jobs
+		}
+	}
+}
+
+// WithUnorderedReport is a option of NewRunner to report tests in order of completion instead of order in spec
+func WithUnorderedReport(unordered bool) Option {
+	return func(r *Runner) {
+		r.unorderedReport = unordered
+	}
+}
+
+// WithMaxFailures is a option of NewRunner to stop running new tests after the given number of failures across specs
+func WithMaxFailures(n int) Option {
+	return func(r *Runner) {
+		if n > 0 {
+			r.maxFailures = n
+		}
+	}
+}
 
-func NewRunner() *Runner {
-	return &Runner{}
+func NewRunner(opts ...Option) *Runner {
+	r := &Runner{jobs: 1}
+	for _, o := range opts {
+		o(r)
+	}
+
+	return r
 }
 
-func (r *Runner) RunTests(name string, tests []*model.Test, reporter *reporter.Reporter) ([]*model.TestResult, error) {
-	results := make([]*model.TestResult, 0, len(tests))
+/*
+RunTests runs tests with r.jobs workers and reports them.
 
-	if err := reporter.OnRunStart(); err != nil {
+A test with Serial and hooks of beforeAll and afterAll are run after all running tests are completed,
+so they never run concurrently with other tests. Skipped tests are reported without being run.
+skipIf and onlyIf conditions of a test are evaluated after beforeAll hooks of its groups.
+A failure of afterAll hook is reported as a failed pseudo test of the group after its tests, so the tests keep their results.
+
+When the number of failures reaches r.maxFailures, the remaining tests are not run
+and stored in the result as not run without being reported. afterAll hooks of entered groups are still run.
+*/
+func (r *Runner) RunTests(name string, tests []*model.Test, reporter *reporter.Reporter) (_ *model.SpecResult, err error) {
+	if err := reporter.OnSpecStart(name); err != nil {
 		return nil, err
 	}
-	for _, t := range tests {
-		if err := reporter.OnTestStart(t); err != nil {
+	start := time.Now()
+
+	rs := newRunState(tests, reporter, r.unorderedReport)
+	sem := make(chan struct{}, r.jobs)
+	var wg sync.WaitGroup
+
+	var entered []*model.Group
+	defer func() {
+		wg.Wait()
+		// afterAll hooks should be run even when the run is aborted
+		if err != nil {
+			for i := len(entered) - 1; i >= 0; i-- {
+				runHooks("afterAll", entered[i].AfterAll)
+			}
+		}
+	}()
+
+	// done is the index of the last test which is already stored in rs
+	done := -1
+
+	// leave runs afterAll hooks of the entered groups until n groups remain, after the i-th test.
+	// Skipped tests of each group following the i-th test are stored before its afterAll hooks,
+	// so that the failure of the hooks is reported after all tests of the group.
+	leave := func(i int, n int) error {
+		for len(entered) > n {
+			g := entered[len(entered)-1]
+			entered = entered[:len(entered)-1]
+			for i+1 < len(tests) && tests[i+1].IsSkipped() && inGroup(tests[i+1], g) {
+				i++
+				rs.start(i)
+				rs.complete(i, tests[i].SkippedResult(), nil)
+				done = i
+			}
+			failure, err := runHooks("afterAll", g.AfterAll)
+			if err != nil {
+				return err
+			}
+			if failure != nil {
+				rs.addHookFailure(i, g, tests[i].SpecFilename, failure)
+			}
+		}
+
+		return nil
+	}
+
+	beforeAllFailures := make(map[*model.Group]*model.AssertionMessage)
+	for i, t := range tests {
+		if i <= done {
+			continue
+		}
+		if err := rs.error(); err != nil {
 			return nil, err
 		}
 
-		tr, err := t.Run()
+		if r.maxFailures > 0 {
+			// wait for a free worker so that the failures of completed tests are counted
+			sem <- struct{}{}
+			<-sem
+			if r.failures+rs.numberOfFailures() >= r.maxFailures {
+				wg.Wait()
+				done = i - 1
+				if err := leave(i-1, 0); err != nil {
+					return nil, err
+				}
+				rs.notRun(done+1, fmt.Sprintf("aborted after %d failures", r.maxFailures))
+				break
+			}
+		}
+
+		// skipped tests are reported without entering their groups, so hooks are not run for them.
+		// Tests skipped by conditions enter their groups because the conditions are evaluated after beforeAll hooks.
+		if t.IsSkipped() {
+			rs.start(i)
+			rs.complete(i, t.SkippedResult(), nil)
+			continue
+		}
+
+		groups := t.Group.GetPath()
+		entering := groups[len(entered):]
+		if t.Serial || hasHooks(entering, func(g *model.Group) []*model.Hook { return g.BeforeAll }) {
+			wg.Wait()
+		}
+		for _, g := range entering {
+			entered = append(entered, g)
+			if failure, err := runHooks("beforeAll", g.BeforeAll); err != nil {
+				return nil, err
+			} else if failure != nil {
+				beforeAllFailures[g] = failure
+			}
+		}
+
+		failures := make([]*model.AssertionMessage, 0)
+		for _, g := range groups {
+			if failure, ok := beforeAllFailures[g]; ok {
+				failures = append(failures, failure)
+			}
+		}
+
+		// conditions are evaluated after beforeAll hooks, so they can check the state set up by the hooks
+		if len(failures) == 0 {
+			if err := t.EvalConditions(); err != nil {
+				return nil, err
+			}
+		}
+
+		var nextGroups []*model.Group
+		for _, next := range tests[i+1:] {
+			if !next.IsSkipped() {
+				nextGroups = next.Group.GetPath()
+				break
+			}
+		}
+		leaving := append([]*model.Group{}, groups[commonPrefixLen(groups, nextGroups):]...)
+		needsAfterAll := hasHooks(leaving, func(g *model.Group) []*model.Hook { return g.AfterAll })
+
+		if t.IsSkipped() {
+			rs.start(i)
+			rs.complete(i, t.SkippedResult(), nil)
+		} else {
+			sem <- struct{}{}
+			wg.Add(1)
+			go func(i int, t *model.Test) {
+				defer wg.Done()
+				rs.start(i)
+				tr, err := runTest(t, groups, failures)
+				rs.complete(i, tr, err)
+				<-sem
+			}(i, t)
+		}
+
+		if t.Serial || needsAfterAll {
+			wg.Wait()
+		}
+
+		if err := leave(i, len(groups)-len(leaving)); err != nil {
+			return nil, err
+		}
+	}
+
+	wg.Wait()
+	if err := rs.error(); err != nil {
+		return nil, err
+	}
+
+	sr := model.NewSpecResult(name, rs.allResults())
+	sr.Duration = time.Since(start)
+	r.failures += sr.Summary.NumberOfFailed
+	if err := reporter.OnSpecComplete(sr); err != nil {
+		return nil, err
+	}
+
+	return sr, nil
+}
+
+// inGroup returns whether t belongs to g directly or through nested groups
+func inGroup(t *model.Test, g *model.Group) bool {
+	for _, tg := range t.Group.GetPath() {
+		if tg == g {
+			return true
+		}
+	}
+
+	return false
+}
+
+func hasHooks(groups []*model.Group, hooksOf func(g *model.Group) []*model.Hook) bool {
+	for _, g := range groups {
+		if len(hooksOf(g)) != 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// runState collects results from workers and reports them
+type runState struct {
+	mu        sync.Mutex
+	tests     []*model.Test
+	results   []*model.TestResult
+	started   []bool
+	finished  []bool
+	reporter  *reporter.Reporter
+	unordered bool
+	// next is the index of the test to be reported next in ordered mode
+	next             int
+	nextStartEmitted bool
+	failures         int
+	// hookFailures are the pseudo results of failed afterAll hooks
+	hookFailures []hookFailure
+	err          error
+}
+
+// hookFailure is a pseudo result of failed afterAll hook reported after the test at index
+type hookFailure struct {
+	index int
+	tr    *model.TestResult
+}
+
+func newRunState(tests []*model.Test, reporter *reporter.Reporter, unordered bool) *runState {
+	return &runState{
+		tests:     tests,
+		results:   make([]*model.TestResult, len(tests)),
+		started:   make([]bool, len(tests)),
+		finished:  make([]bool, len(tests)),
+		reporter:  reporter,
+		unordered: unordered,
+	}
+}
+
+func (rs *runState) error() error {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	return rs.err
+}
+
+func (rs *runState) numberOfFailures() int {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	return rs.failures
+}
+
+// notRun stores the tests from the i-th as not run
+func (rs *runState) notRun(i int, reason string) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	for ; i < len(rs.tests); i++ {
+		rs.results[i] = rs.tests[i].NotRunResult(reason)
+		rs.finished[i] = true
+	}
+	if !rs.unordered {
+		rs.flush()
+	}
+}
+
+func (rs *runState) start(i int) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	rs.started[i] = true
+	if rs.unordered {
+		rs.report(func() error { return rs.reporter.OnTestStart(rs.tests[i]) })
+	} else {
+		rs.flush()
+	}
+}
+
+// complete stores the result of the i-th test and marks it as finished
+func (rs *runState) complete(i int, tr *model.TestResult, err error) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	if err != nil {
+		if rs.err == nil {
+			rs.err = err
+		}
+		return
+	}
+
+	rs.results[i] = tr
+	if !tr.IsSuccess {
+		rs.failures++
+	}
+	rs.finished[i] = true
+	if rs.unordered {
+		rs.report(func() error { return rs.reporter.OnTestComplete(rs.tests[i], tr) })
+	} else {
+		rs.flush()
+	}
+}
+
+// addHookFailure reports the failure of afterAll hook of g as a pseudo test after the i-th test.
+// It should be called when all tests to the i-th are finished, so the pseudo test is reported in order.
+func (rs *runState) addHookFailure(i int, g *model.Group, specFilename string, failure *model.AssertionMessage) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	t := &model.Test{Name: failure.Name + " hook", SpecFilename: specFilename, Group: g}
+	tr := &model.TestResult{
+		Name:         t.GetName(),
+		Groups:       g.GetNames(),
+		Status:       model.TestFailed,
+		Messages:     make([]*model.AssertionMessage, 0),
+		HookFailures: []*model.AssertionMessage{failure},
+	}
+	rs.hookFailures = append(rs.hookFailures, hookFailure{index: i, tr: tr})
+	rs.failures++
+	rs.report(func() error { return rs.reporter.OnTestStart(t) })
+	rs.report(func() error { return rs.reporter.OnTestComplete(t, tr) })
+}
+
+// allResults returns the results of tests and the pseudo results of afterAll hooks in order
+func (rs *runState) allResults() []*model.TestResult {
+	results := make([]*model.TestResult, 0, len(rs.results)+len(rs.hookFailures))
+	j := 0
+	for i, tr := range rs.results {
+		results = append(results, tr)
+		for ; j < len(rs.hookFailures) && rs.hookFailures[j].index == i; j++ {
+			results = append(results, rs.hookFailures[j].tr)
+		}
+	}
+
+	return results
+}
+
+// flush reports tests in order of the spec as long as possible
+func (rs *runState) flush() {
+	for rs.next < len(rs.tests) {
+		// tests not run are not reported
+		if !rs.started[rs.next] && rs.finished[rs.next] {
+			rs.next++
+			continue
+		}
+
+		if rs.started[rs.next] && !rs.nextStartEmitted {
+			t := rs.tests[rs.next]
+			rs.report(func() error { return rs.reporter.OnTestStart(t) })
+			rs.nextStartEmitted = true
+		}
+
+		if !rs.finished[rs.next] || !rs.nextStartEmitted {
+			return
+		}
+
+		t, tr := rs.tests[rs.next], rs.results[rs.next]
+		rs.report(func() error { return rs.reporter.OnTestComplete(t, tr) })
+		rs.next++
+		rs.nextStartEmitted = false
+	}
+}
+
+func (rs *runState) report(f func() error) {
+	if rs.err != nil {
+		return
+	}
+
+	rs.err = f()
+}
+
+// runTest runs t surrounded by beforeEach and afterEach hooks of groups.
+// t is not run when beforeAll or beforeEach hooks of its groups are failed, but afterEach hooks are always run.
+func runTest(t *model.Test, groups []*model.Group, beforeAllFailures []*model.AssertionMessage) (*model.TestResult, error) {
+	hookFailures := append([]*model.AssertionMessage{}, beforeAllFailures...)
+
+	if len(hookFailures) == 0 {
+		for _, g := range groups {
+			failure, err := runHooks("beforeEach", g.BeforeEach)
+			if err != nil {
+				return nil, err
+			}
+			if failure != nil {
+				hookFailures = append(hookFailures, failure)
+				break
+			}
+		}
+	}
+
+	var tr *model.TestResult
+	if len(hookFailures) == 0 {
+		var err error
+		tr, err = t.Run()
 		if err != nil {
 			return nil, err
 		}
+	} else {
+		tr = &model.TestResult{
+			Name:     t.GetName(),
+			Groups:   t.Group.GetNames(),
+			Messages: make([]*model.AssertionMessage, 0),
+		}
+	}
 
-		if err := reporter.OnTestComplete(t, tr); err != nil {
+	for i := len(groups) - 1; i >= 0; i-- {
+		failure, err := runHooks("afterEach", groups[i].AfterEach)
+		if err != nil {
 			return nil, err
 		}
-		results = append(results, tr)
+		if failure != nil {
+			hookFailures = append(hookFailures, failure)
+		}
 	}
-	sr := model.NewSpecResult(name, results)
-	if err := reporter.OnRunComplete(sr); err != nil {
-		return nil, err
+
+	if len(hookFailures) != 0 {
+		tr.HookFailures = hookFailures
+		tr.Fail()
+	}
+
+	return tr, nil
+}
+
+// runHooks runs hooks until one of them is failed and returns the failure
+func runHooks(name string, hooks []*model.Hook) (*model.AssertionMessage, error) {
+	for _, h := range hooks {
+		message, err := h.Run()
+		if err != nil {
+			return nil, err
+		}
+		if len(message) != 0 {
+			return &model.AssertionMessage{Name: name, Message: message}, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func commonPrefixLen(xs, ys []*model.Group) int {
+	n := 0
+	for n < len(xs) && n < len(ys) && xs[n] == ys[n] {
+		n++
 	}
 
-	return results, nil
+	return n
 }
@@ -0,0 +1,410 @@
+package runner
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/autopp/spexec/pkg/model"
+	"github.com/autopp/spexec/pkg/reporter"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type recordingFormatter struct {
+	events []string
+}
+
+func (f *recordingFormatter) OnRunStart(w *reporter.Writer) error {
+	return nil
+}
+
+func (f *recordingFormatter) OnSpecStart(w *reporter.Writer, name string) error {
+	return nil
+}
+
+func (f *recordingFormatter) OnTestStart(w *reporter.Writer, t *model.Test) error {
+	f.events = append(f.events, "start "+t.Name)
+	return nil
+}
+
+func (f *recordingFormatter) OnTestComplete(w *reporter.Writer, t *model.Test, tr *model.TestResult) error {
+	f.events = append(f.events, "complete "+t.Name)
+	return nil
+}
+
+func (f *recordingFormatter) OnSpecComplete(w *reporter.Writer, sr *model.SpecResult) error {
+	return nil
+}
+
+func (f *recordingFormatter) OnRunComplete(w *reporter.Writer, rr *model.RunResult) error {
+	return nil
+}
+
+var _ = Describe("Runner", func() {
+	Describe("RunTests()", func() {
+		var dir string
+		var r *reporter.Reporter
+
+		logCommand := func(message string) []model.StringExpr {
+			return []model.StringExpr{
+				model.NewLiteralStringExpr("sh"),
+				model.NewLiteralStringExpr("-c"),
+				model.NewLiteralStringExpr("echo " + message + " >> log"),
+			}
+		}
+
+		hook := func(message string) *model.Hook {
+			return &model.Hook{Command: logCommand(message), Dir: dir}
+		}
+
+		failingHook := func() *model.Hook {
+			return &model.Hook{Command: []model.StringExpr{model.NewLiteralStringExpr("false")}, Dir: dir}
+		}
+
+		readLog := func() string {
+			b, _ := os.ReadFile(filepath.Join(dir, "log"))
+			return string(b)
+		}
+
+		JustBeforeEach(func() {
+			dir = GinkgoT().TempDir()
+			r, _ = reporter.New(reporter.WithWriter(&bytes.Buffer{}))
+		})
+
+		It("runs hooks of groups around tests", func() {
+			root := &model.Group{
+				BeforeAll:  []*model.Hook{hook("root-beforeAll")},
+				BeforeEach: []*model.Hook{hook("root-beforeEach")},
+				AfterEach:  []*model.Hook{hook("root-afterEach")},
+				AfterAll:   []*model.Hook{hook("root-afterAll")},
+			}
+			group := &model.Group{
+				Name:       "group",
+				Parent:     root,
+				BeforeAll:  []*model.Hook{hook("group-beforeAll")},
+				BeforeEach: []*model.Hook{hook("group-beforeEach")},
+				AfterEach:  []*model.Hook{hook("group-afterEach")},
+				AfterAll:   []*model.Hook{hook("group-afterAll")},
+			}
+			tests := []*model.Test{
+				{Group: root, Dir: dir, Command: logCommand("test1")},
+				{Group: group, Dir: dir, Command: logCommand("test2")},
+				{Group: group, Dir: dir, Command: logCommand("test3")},
+			}
+
+			sr, err := NewRunner().RunTests("spec.yaml", tests, r)
+			Expect(err).NotTo(HaveOccurred())
+			results := sr.TestResults
+			Expect(results).To(HaveLen(3))
+			Expect(readLog()).To(Equal(`root-beforeAll
+root-beforeEach
+test1
+root-afterEach
+group-beforeAll
+root-beforeEach
+group-beforeEach
+test2
+group-afterEach
+root-afterEach
+root-beforeEach
+group-beforeEach
+test3
+group-afterEach
+root-afterEach
+group-afterAll
+root-afterAll
+`))
+		})
+
+		It("does not run tests in the group whose beforeAll is failed", func() {
+			group := &model.Group{
+				Name:      "group",
+				BeforeAll: []*model.Hook{failingHook()},
+				AfterAll:  []*model.Hook{hook("afterAll")},
+			}
+			tests := []*model.Test{
+				{Group: group, Dir: dir, Command: logCommand("test1")},
+				{Group: group, Dir: dir, Command: logCommand("test2")},
+			}
+
+			sr, err := NewRunner().RunTests("spec.yaml", tests, r)
+			Expect(err).NotTo(HaveOccurred())
+			results := sr.TestResults
+			Expect(readLog()).To(Equal("afterAll\n"))
+			for _, tr := range results {
+				Expect(tr.IsSuccess).To(BeFalse())
+				Expect(tr.HookFailures).To(Equal([]*model.AssertionMessage{{Name: "beforeAll", Message: "`false` failed: process exited with status 1"}}))
+			}
+		})
+
+		It("runs afterEach and reports the failure of beforeEach", func() {
+			group := &model.Group{
+				Name:       "group",
+				BeforeEach: []*model.Hook{failingHook()},
+				AfterEach:  []*model.Hook{hook("afterEach")},
+			}
+			tests := []*model.Test{
+				{Group: group, Dir: dir, Command: logCommand("test1")},
+			}
+
+			sr, err := NewRunner().RunTests("spec.yaml", tests, r)
+			Expect(err).NotTo(HaveOccurred())
+			results := sr.TestResults
+			Expect(readLog()).To(Equal("afterEach\n"))
+			Expect(results[0].IsSuccess).To(BeFalse())
+			Expect(results[0].HookFailures).To(Equal([]*model.AssertionMessage{{Name: "beforeEach", Message: "`false` failed: process exited with status 1"}}))
+		})
+
+		It("reports the failure of afterAll as a pseudo test after the tests of the group", func() {
+			group := &model.Group{
+				Name:     "group",
+				AfterAll: []*model.Hook{failingHook()},
+			}
+			tests := []*model.Test{
+				{Name: "test1", Group: group, Dir: dir, Command: logCommand("test1")},
+				{Name: "test2", Group: group, Dir: dir, Command: logCommand("test2")},
+				{Name: "test3", Group: &model.Group{}, Dir: dir, Command: logCommand("test3")},
+			}
+			f := &recordingFormatter{}
+			r, _ = reporter.New(reporter.WithTarget(f, &bytes.Buffer{}, false))
+
+			sr, err := NewRunner().RunTests("spec.yaml", tests, r)
+			Expect(err).NotTo(HaveOccurred())
+			results := sr.TestResults
+			Expect(results).To(HaveLen(4))
+			Expect(results[0].IsSuccess).To(BeTrue())
+			Expect(results[1].IsSuccess).To(BeTrue())
+			Expect(results[2].Name).To(Equal("group afterAll hook"))
+			Expect(results[2].IsSuccess).To(BeFalse())
+			Expect(results[2].HookFailures).To(Equal([]*model.AssertionMessage{{Name: "afterAll", Message: "`false` failed: process exited with status 1"}}))
+			Expect(results[3].IsSuccess).To(BeTrue())
+			Expect(sr.Summary.NumberOfFailed).To(Equal(1))
+			Expect(f.events).To(Equal([]string{
+				"start test1", "complete test1",
+				"start test2", "complete test2",
+				"start afterAll hook", "complete afterAll hook",
+				"start test3", "complete test3",
+			}))
+		})
+
+		It("reports the failure of afterAll after skipped tests of the group", func() {
+			group := &model.Group{
+				Name:     "group",
+				AfterAll: []*model.Hook{failingHook()},
+			}
+			tests := []*model.Test{
+				{Name: "test1", Group: group, Dir: dir, Command: logCommand("test1")},
+				{Name: "test2", Group: group, Dir: dir, Command: logCommand("test2"), SkipReason: "filtered"},
+				{Name: "test3", Group: &model.Group{}, Dir: dir, Command: logCommand("test3")},
+			}
+			f := &recordingFormatter{}
+			r, _ = reporter.New(reporter.WithTarget(f, &bytes.Buffer{}, false))
+
+			sr, err := NewRunner().RunTests("spec.yaml", tests, r)
+			Expect(err).NotTo(HaveOccurred())
+			results := sr.TestResults
+			Expect(results).To(HaveLen(4))
+			Expect(results[1].Status).To(Equal(model.TestSkipped))
+			Expect(results[2].Name).To(Equal("group afterAll hook"))
+			Expect(f.events).To(Equal([]string{
+				"start test1", "complete test1",
+				"start test2", "complete test2",
+				"start afterAll hook", "complete afterAll hook",
+				"start test3", "complete test3",
+			}))
+		})
+
+		It("reports skipped tests without running them and hooks of their groups", func() {
+			skippedGroup := &model.Group{
+				Name:      "skipped",
+				BeforeAll: []*model.Hook{hook("skipped-beforeAll")},
+				AfterAll:  []*model.Hook{hook("skipped-afterAll")},
+			}
+			group := &model.Group{
+				Name:     "group",
+				AfterAll: []*model.Hook{hook("group-afterAll")},
+			}
+			tests := []*model.Test{
+				{Group: group, Dir: dir, Command: logCommand("test1")},
+				{Group: skippedGroup, Dir: dir, Command: logCommand("test2"), SkipReason: "filtered"},
+				{Group: group, Dir: dir, Command: logCommand("test3")},
+				{Group: group, Dir: dir, Command: logCommand("test4"), SkipReason: "filtered"},
+			}
+
+			sr, err := NewRunner().RunTests("spec.yaml", tests, r)
+			Expect(err).NotTo(HaveOccurred())
+			results := sr.TestResults
+			Expect(readLog()).To(Equal("test1\ntest3\ngroup-afterAll\n"))
+			Expect(results[1].Status).To(Equal(model.TestSkipped))
+			Expect(results[1].SkipReason).To(Equal("filtered"))
+			Expect(results[3].Status).To(Equal(model.TestSkipped))
+		})
+
+		It("skips tests by their conditions", func() {
+			group := &model.Group{
+				Name:     "group",
+				AfterAll: []*model.Hook{hook("group-afterAll")},
+			}
+			tests := []*model.Test{
+				{Group: group, Dir: dir, Command: logCommand("test1")},
+				{Group: group, Dir: dir, Command: logCommand("test2"), OnlyIf: []model.Condition{&model.ExprCondition{Value: false}}},
+			}
+
+			sr, err := NewRunner().RunTests("spec.yaml", tests, r)
+			Expect(err).NotTo(HaveOccurred())
+			results := sr.TestResults
+			Expect(readLog()).To(Equal("test1\ngroup-afterAll\n"))
+			Expect(results[1].Status).To(Equal(model.TestSkipped))
+			Expect(results[1].SkipReason).To(Equal("onlyIf: expr is false"))
+		})
+
+		It("evaluates conditions after beforeAll hooks of their groups", func() {
+			group := &model.Group{
+				Name:      "group",
+				BeforeAll: []*model.Hook{hook("setup")},
+			}
+			probe := &model.ProbeCondition{Probe: &model.Hook{Command: []model.StringExpr{
+				model.NewLiteralStringExpr("test"),
+				model.NewLiteralStringExpr("-f"),
+				model.NewLiteralStringExpr("log"),
+			}, Dir: dir}}
+			tests := []*model.Test{
+				{Group: group, Dir: dir, Command: logCommand("test1"), OnlyIf: []model.Condition{probe}},
+			}
+
+			sr, err := NewRunner().RunTests("spec.yaml", tests, r)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sr.TestResults[0].Status).NotTo(Equal(model.TestSkipped))
+			Expect(readLog()).To(Equal("setup\ntest1\n"))
+		})
+
+		Describe("with max failures", func() {
+			It("does not run the tests after the failures and runs afterAll hooks", func() {
+				failing := &model.Group{
+					Name:       "failing",
+					BeforeEach: []*model.Hook{failingHook()},
+					AfterAll:   []*model.Hook{hook("failing-afterAll")},
+				}
+				tests := []*model.Test{
+					{Group: failing, Dir: dir, Command: logCommand("test1")},
+					{Group: failing, Dir: dir, Command: logCommand("test2")},
+					{Group: failing, Dir: dir, Command: logCommand("test3")},
+				}
+
+				sr, err := NewRunner(WithMaxFailures(2)).RunTests("spec.yaml", tests, r)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(readLog()).To(Equal("failing-afterAll\n"))
+				Expect(sr.Summary.NumberOfFailed).To(Equal(2))
+				Expect(sr.Summary.NumberOfNotRun).To(Equal(1))
+				Expect(sr.TestResults[2].SkipReason).To(Equal("aborted after 2 failures"))
+			})
+
+			It("counts the failures across specs", func() {
+				failing := &model.Group{Name: "failing", BeforeEach: []*model.Hook{failingHook()}}
+				runner := NewRunner(WithMaxFailures(1))
+
+				sr, err := runner.RunTests("spec1.yaml", []*model.Test{{Group: failing, Dir: dir, Command: logCommand("test1")}}, r)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(sr.Summary.NumberOfFailed).To(Equal(1))
+
+				sr, err = runner.RunTests("spec2.yaml", []*model.Test{{Group: &model.Group{}, Dir: dir, Command: logCommand("test2")}}, r)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(readLog()).To(Equal(""))
+				Expect(sr.Summary.NumberOfNotRun).To(Equal(1))
+			})
+		})
+
+		Describe("with jobs", func() {
+			var f *recordingFormatter
+
+			shCommand := func(script string) []model.StringExpr {
+				return []model.StringExpr{
+					model.NewLiteralStringExpr("sh"),
+					model.NewLiteralStringExpr("-c"),
+					model.NewLiteralStringExpr(script),
+				}
+			}
+
+			JustBeforeEach(func() {
+				f = &recordingFormatter{}
+				r, _ = reporter.New(reporter.WithWriter(&bytes.Buffer{}), reporter.WithFormatter(f))
+			})
+
+			It("runs tests concurrently", func() {
+				tests := []*model.Test{
+					{Name: "test1", Dir: dir, Timeout: 5 * time.Second, Command: shCommand("touch a; while [ ! -f b ]; do sleep 0.01; done")},
+					{Name: "test2", Dir: dir, Timeout: 5 * time.Second, Command: shCommand("touch b; while [ ! -f a ]; do sleep 0.01; done")},
+				}
+
+				sr, err := NewRunner(WithJobs(2)).RunTests("spec.yaml", tests, r)
+				Expect(err).NotTo(HaveOccurred())
+				results := sr.TestResults
+				Expect(results[0].IsSuccess).To(BeTrue())
+				Expect(results[1].IsSuccess).To(BeTrue())
+			})
+
+			It("reports tests in order of the spec", func() {
+				tests := []*model.Test{
+					{Name: "test1", Dir: dir, Command: shCommand("sleep 0.2")},
+					{Name: "test2", Dir: dir, Command: shCommand("true")},
+				}
+
+				sr, err := NewRunner(WithJobs(2)).RunTests("spec.yaml", tests, r)
+				Expect(err).NotTo(HaveOccurred())
+				results := sr.TestResults
+				Expect(results[0].Name).To(Equal("test1"))
+				Expect(results[1].Name).To(Equal("test2"))
+				Expect(f.events).To(Equal([]string{"start test1", "complete test1", "start test2", "complete test2"}))
+			})
+
+			It("reports tests in order of completion with WithUnorderedReport", func() {
+				tests := []*model.Test{
+					{Name: "test1", Dir: dir, Command: shCommand("sleep 0.2")},
+					{Name: "test2", Dir: dir, Command: shCommand("true")},
+				}
+
+				sr, err := NewRunner(WithJobs(2), WithUnorderedReport(true)).RunTests("spec.yaml", tests, r)
+				Expect(err).NotTo(HaveOccurred())
+				results := sr.TestResults
+				Expect(results[0].Name).To(Equal("test1"))
+				Expect(results[1].Name).To(Equal("test2"))
+				Expect(f.events).To(HaveLen(4))
+				Expect(f.events[2:]).To(Equal([]string{"complete test2", "complete test1"}))
+			})
+
+			It("does not run serial tests concurrently with other tests", func() {
+				tests := []*model.Test{
+					{Name: "test1", Dir: dir, Command: shCommand("sleep 0.2; echo test1 >> log")},
+					{Name: "test2", Dir: dir, Command: shCommand("echo test2 >> log"), Serial: true},
+					{Name: "test3", Dir: dir, Command: shCommand("sleep 0.2; echo test3 >> log")},
+					{Name: "test4", Dir: dir, Command: shCommand("echo test4 >> log")},
+				}
+
+				_, err := NewRunner(WithJobs(4)).RunTests("spec.yaml", tests, r)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(readLog()).To(Equal("test1\ntest2\ntest4\ntest3\n"))
+			})
+
+			It("runs hooks of groups in the same order as without jobs", func() {
+				group := &model.Group{
+					Name:      "group",
+					BeforeAll: []*model.Hook{hook("beforeAll")},
+					AfterAll:  []*model.Hook{hook("afterAll")},
+				}
+				tests := []*model.Test{
+					{Name: "test1", Dir: dir, Command: shCommand("sleep 0.2; echo test1 >> log")},
+					{Name: "test2", Group: group, Dir: dir, Command: shCommand("sleep 0.1; echo test2 >> log")},
+					{Name: "test3", Group: group, Dir: dir, Command: shCommand("echo test3 >> log")},
+					{Name: "test4", Dir: dir, Command: shCommand("echo test4 >> log")},
+				}
+
+				_, err := NewRunner(WithJobs(4)).RunTests("spec.yaml", tests, r)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(readLog()).To(Equal("test1\nbeforeAll\ntest3\ntest2\nafterAll\ntest4\n"))
+			})
+		})
+	})
+})
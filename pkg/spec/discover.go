@@ -0,0 +1,104 @@
+// Copyright (C) 2021-2023	 Akira Tanimura (@autopp)
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spec
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/autopp/spexec/pkg/errors"
+	"github.com/autopp/spexec/pkg/util"
+)
+
+// DefaultSpecPatterns are the patterns of the spec filenames searched in directories
+var DefaultSpecPatterns = []string{"*_spec.yaml", "*.spexec.yaml"}
+
+// Discover returns the spec files given as args.
+// Directories are searched recursively for the files whose name matches to one of patterns,
+// and glob patterns (with "**" for any directories) are expanded.
+// The files found for each arg are sorted and the ones matched to excludes are dropped.
+// Patterns and excludes should be already validated.
+func Discover(args []string, patterns []string, excludes []string) ([]string, error) {
+	filenames := make([]string, 0, len(args))
+	seen := map[string]bool{}
+	add := func(filename string) {
+		if !seen[filename] {
+			seen[filename] = true
+			filenames = append(filenames, filename)
+		}
+	}
+
+	for _, arg := range args {
+		var match func(filename string) bool
+		root := arg
+		if util.HasGlobMeta(arg) {
+			pattern := filepath.Clean(arg)
+			root = util.GlobBase(pattern)
+			match = func(filename string) bool {
+				matched, _ := util.MatchGlob(pattern, filename)
+				return matched
+			}
+		} else if info, err := os.Stat(arg); err == nil && info.IsDir() {
+			match = func(filename string) bool {
+				return matchAny(patterns, filepath.Base(filename))
+			}
+		} else {
+			// file is given as is, and error is reported at parsing it
+			add(arg)
+			continue
+		}
+
+		found := make([]string, 0)
+		err := filepath.WalkDir(root, func(filename string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() && match(filename) && !isExcluded(excludes, filename) {
+				found = append(found, filename)
+			}
+			return nil
+		})
+		if err != nil && !(os.IsNotExist(err) && root != arg) {
+			return nil, errors.Wrap(errors.ErrInvalidSpec, err)
+		}
+
+		if len(found) == 0 {
+			return nil, errors.Errorf(errors.ErrInvalidSpec, "no spec is found by %s", arg)
+		}
+		sort.Strings(found)
+		for _, filename := range found {
+			add(filename)
+		}
+	}
+
+	return filenames, nil
+}
+
+func matchAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := util.MatchGlob(pattern, name); matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isExcluded returns whether filename or its base name matches to one of excludes
+func isExcluded(excludes []string, filename string) bool {
+	return matchAny(excludes, filename) || matchAny(excludes, filepath.Base(filename))
+}
@@ -0,0 +1,64 @@
+package spec
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Discover()", func() {
+	var dir string
+
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+		for _, filename := range []string{
+			"b_spec.yaml",
+			"a.spexec.yaml",
+			"other.yaml",
+			"sub/c_spec.yaml",
+			"sub/deep/d.yaml",
+			"skipped/e_spec.yaml",
+		} {
+			path := filepath.Join(dir, filename)
+			Expect(os.MkdirAll(filepath.Dir(path), 0755)).To(Succeed())
+			Expect(os.WriteFile(path, []byte("tests: []\n"), 0644)).To(Succeed())
+		}
+	})
+
+	It("returns the files matched to patterns in the directory recursively with sorted", func() {
+		Expect(Discover([]string{dir}, DefaultSpecPatterns, nil)).To(Equal([]string{
+			filepath.Join(dir, "a.spexec.yaml"),
+			filepath.Join(dir, "b_spec.yaml"),
+			filepath.Join(dir, "skipped/e_spec.yaml"),
+			filepath.Join(dir, "sub/c_spec.yaml"),
+		}))
+	})
+
+	It("returns the files matched to the glob pattern", func() {
+		Expect(Discover([]string{filepath.Join(dir, "sub/**/*.yaml")}, DefaultSpecPatterns, nil)).To(Equal([]string{
+			filepath.Join(dir, "sub/c_spec.yaml"),
+			filepath.Join(dir, "sub/deep/d.yaml"),
+		}))
+	})
+
+	It("drops the files matched to excludes", func() {
+		Expect(Discover([]string{dir}, DefaultSpecPatterns, []string{"**/skipped/**", "b_*"})).To(Equal([]string{
+			filepath.Join(dir, "a.spexec.yaml"),
+			filepath.Join(dir, "sub/c_spec.yaml"),
+		}))
+	})
+
+	It("returns the given files as is without duplication", func() {
+		Expect(Discover([]string{"missing.yaml", filepath.Join(dir, "other.yaml"), "missing.yaml"}, DefaultSpecPatterns, nil)).To(Equal([]string{
+			"missing.yaml",
+			filepath.Join(dir, "other.yaml"),
+		}))
+	})
+
+	It("returns error when no file is found", func() {
+		_, err := Discover([]string{filepath.Join(dir, "**/*.json")}, DefaultSpecPatterns, nil)
+		Expect(err).To(MatchError("no spec is found by " + filepath.Join(dir, "**/*.json")))
+	})
+})
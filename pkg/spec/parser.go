@@ -15,9 +15,12 @@
 package spec
 
 import (
+	"bytes"
 	"io"
 	"os"
 	"path/filepath"
+	"sort"
+	"time"
 
 	"github.com/autopp/spexec/pkg/errors"
 	"github.com/autopp/spexec/pkg/matcher"
@@ -28,65 +31,158 @@ import (
 )
 
 type Parser struct {
-	statusMR *matcher.StatusMatcherRegistry
-	streamMR *matcher.StreamMatcherRegistry
+	statusMR       *matcher.StatusMatcherRegistry
+	streamMR       *matcher.StreamMatcherRegistry
+	defaultTimeout time.Duration
+	defaultEnv     []*template.TemplatableStringVar
 }
 
-func NewParser(statusMR *matcher.StatusMatcherRegistry, streamMR *matcher.StreamMatcherRegistry) *Parser {
-	return &Parser{statusMR, streamMR}
+// Option is functional option of NewParser
+type Option func(p *Parser)
+
+// WithDefaultTimeout is a option of NewParser to specify the timeout of tests and hooks which have no timeout
+func WithDefaultTimeout(timeout time.Duration) Option {
+	return func(p *Parser) {
+		p.defaultTimeout = timeout
+	}
+}
+
+// WithDefaultEnv is a option of NewParser to specify the environment variables given to all tests and hooks
+func WithDefaultEnv(env []util.StringVar) Option {
+	return func(p *Parser) {
+		p.defaultEnv = make([]*template.TemplatableStringVar, len(env))
+		for i, v := range env {
+			p.defaultEnv[i] = &template.TemplatableStringVar{Name: v.Name, Value: model.NewTemplatableFromValue(v.Value)}
+		}
+	}
+}
+
+func NewParser(statusMR *matcher.StatusMatcherRegistry, streamMR *matcher.StreamMatcherRegistry, opts ...Option) *Parser {
+	p := &Parser{statusMR: statusMR, streamMR: streamMR}
+	for _, o := range opts {
+		o(p)
+	}
+
+	return p
 }
 
-func (p *Parser) ParseStdin(env *model.Env, v *model.Validator) ([]*template.TestTemplate, error) {
+func (p *Parser) ParseStdin(env *model.Env, v *model.Validator) (*template.SpecTemplate, error) {
 	return p.parseYAML(env, v, "", os.Stdin)
 }
 
-func (p *Parser) ParseFile(env *model.Env, v *model.Validator, filename string) ([]*template.TestTemplate, error) {
+func (p *Parser) ParseFile(env *model.Env, v *model.Validator, filename string) (*template.SpecTemplate, error) {
 	f, err := os.Open(filename)
 	if err != nil {
 		return nil, errors.Wrap(errors.ErrInvalidSpec, err)
 	}
 	defer f.Close()
 
-	var tests []*template.TestTemplate
+	var st *template.SpecTemplate
 	ext := filepath.Ext(filename)
 	if ext == ".yml" || ext == ".yaml" {
-		tests, err = p.parseYAML(env, v, filename, f)
+		st, err = p.parseYAML(env, v, filename, f)
 	} else {
-		tests, err = p.parseJSON(env, v, filename, f)
+		st, err = p.parseJSON(env, v, filename, f)
 	}
 
-	return tests, err
+	return st, err
 }
 
-func (p *Parser) parseYAML(env *model.Env, v *model.Validator, filename string, in io.Reader) ([]*template.TestTemplate, error) {
-	return p.load(env, v, filename, in, func(in io.Reader, out any) error {
-		return yaml.NewDecoder(in).Decode(out)
-	})
+// ParseVarsFile loads variables from a YAML or JSON file which contains a map
+func (p *Parser) ParseVarsFile(v *model.Validator, filename string) (model.Map, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrInvalidSpec, err)
+	}
+	defer f.Close()
+
+	unmarshal := util.DecodeJSON
+	ext := filepath.Ext(filename)
+	if ext == ".yml" || ext == ".yaml" {
+		unmarshal = decodeYAML
+	}
+
+	var x any
+	if err := unmarshal(f, &x); err != nil {
+		return nil, errors.Wrap(errors.ErrInvalidSpec, err)
+	}
+
+	vars, ok := v.MustBeMap(x)
+	if !ok {
+		return nil, v.Error()
+	}
+
+	for name := range vars {
+		v.InField(name, func() {
+			v.MustBeVariableName(name)
+		})
+	}
+
+	return vars, v.Error()
 }
 
-func (p *Parser) parseJSON(env *model.Env, v *model.Validator, filename string, in io.Reader) ([]*template.TestTemplate, error) {
-	return p.load(env, v, filename, in, util.DecodeJSON)
+func (p *Parser) parseYAML(env *model.Env, v *model.Validator, filename string, in io.Reader) (*template.SpecTemplate, error) {
+	x, err := decodeYAMLWithPositions(v, in)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.loadSpec(env, v, x)
 }
 
-func (p *Parser) load(env *model.Env, v *model.Validator, filename string, b io.Reader, unmarshal func(in io.Reader, out any) error) ([]*template.TestTemplate, error) {
+// DecodeYAMLFile decodes the YAML file and sets the positions in it to v
+func DecodeYAMLFile(v *model.Validator, filename string) (any, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrInvalidSpec, err)
+	}
+	defer f.Close()
+
+	return decodeYAMLWithPositions(v, f)
+}
+
+func decodeYAMLWithPositions(v *model.Validator, in io.Reader) (any, error) {
+	var node yaml.Node
+	if err := yaml.NewDecoder(in).Decode(&node); err != nil {
+		return nil, errors.Wrap(errors.ErrInvalidSpec, err)
+	}
+
 	var x any
-	err := unmarshal(b, &x)
+	if err := node.Decode(&x); err != nil {
+		return nil, errors.Wrap(errors.ErrInvalidSpec, err)
+	}
+	v.SetPositions(yamlPositions(&node))
+
+	return x, nil
+}
+
+func (p *Parser) parseJSON(env *model.Env, v *model.Validator, filename string, in io.Reader) (*template.SpecTemplate, error) {
+	b, err := io.ReadAll(in)
 	if err != nil {
 		return nil, errors.Wrap(errors.ErrInvalidSpec, err)
 	}
 
+	var x any
+	if err := util.DecodeJSON(bytes.NewReader(b), &x); err != nil {
+		return nil, errors.Wrap(errors.ErrInvalidSpec, err)
+	}
+	// positions are only for messages, so the spec can be loaded without them
+	if positions, err := jsonPositions(b); err == nil {
+		v.SetPositions(positions)
+	}
+
 	return p.loadSpec(env, v, x)
 }
 
-func (p *Parser) loadSpec(env *model.Env, v *model.Validator, c any) ([]*template.TestTemplate, error) {
+func (p *Parser) loadSpec(env *model.Env, v *model.Validator, c any) (*template.SpecTemplate, error) {
 	cmap, ok := v.MustBeMap(c)
 	if !ok {
 		return nil, v.Error()
 	}
 
-	ts := make([]*template.TestTemplate, 0)
+	st := &template.SpecTemplate{Vars: make([]*template.TemplatableVar, 0), Tests: make([]*template.TestTemplate, 0), Groups: make([]*template.GroupTemplate, 0)}
 
-	v.MustContainOnly(cmap, "spexec", "tests")
+	v.MustContainOnly(cmap, "spexec", "vars", "beforeAll", "beforeEach", "afterEach", "afterAll", "tests", "groups")
 
 	version, exists, ok := v.MayHaveString(cmap, "spexec")
 	if ok && exists {
@@ -97,49 +193,323 @@ func (p *Parser) loadSpec(env *model.Env, v *model.Validator, c any) ([]*templat
 		}
 	}
 
-	v.MustHaveSeq(cmap, "tests", func(tcs model.Seq) {
+	v.MayHaveMap(cmap, "vars", func(vars model.Map) {
+		st.Vars = p.loadVars(v, vars)
+	})
+
+	defaults := &testDefaults{dir: v.GetDir(), timeout: p.defaultTimeout, env: p.defaultEnv}
+	st.Hooks = p.loadHooks(v, cmap, defaults)
+	st.Tests, st.Groups = p.loadChildren(env, v, cmap, defaults)
+
+	return st, v.Error()
+}
+
+// testDefaults holds the settings inherited from the enclosing groups
+type testDefaults struct {
+	dir     string
+	timeout time.Duration
+	env     []*template.TemplatableStringVar
+	tags    []string
+}
+
+func (p *Parser) loadChildren(env *model.Env, v *model.Validator, m model.Map, defaults *testDefaults) ([]*template.TestTemplate, []*template.GroupTemplate) {
+	tests := make([]*template.TestTemplate, 0)
+	groups := make([]*template.GroupTemplate, 0)
+
+	loadTests := func(tcs model.Seq) {
 		v.ForInSeq(tcs, func(i int, tc any) bool {
-			t := p.loadTest(env, v, tc)
-			ts = append(ts, t)
+			t := p.loadTest(env, v, tc, defaults)
+			tests = append(tests, t)
 			return t != nil
 		})
+	}
+
+	_, hasGroups, _ := v.MayHaveSeq(m, "groups", func(gcs model.Seq) {
+		v.ForInSeq(gcs, func(i int, gc any) bool {
+			g := p.loadGroup(env, v, gc, defaults)
+			groups = append(groups, g)
+			return g != nil
+		})
 	})
 
-	return ts, v.Error()
+	if hasGroups {
+		v.MayHaveSeq(m, "tests", loadTests)
+	} else {
+		v.MustHaveSeq(m, "tests", loadTests)
+	}
+
+	return tests, groups
+}
+
+func (p *Parser) loadGroup(env *model.Env, v *model.Validator, x any, defaults *testDefaults) *template.GroupTemplate {
+	gc, ok := v.MustBeMap(x)
+	if !ok {
+		return nil
+	}
+
+	v.MustContainOnly(gc, "name", "vars", "env", "dir", "timeout", "tags", "beforeAll", "beforeEach", "afterEach", "afterAll", "tests", "groups")
+
+	gt := &template.GroupTemplate{Vars: make([]*template.TemplatableVar, 0)}
+	gt.Name, _ = v.MustHaveTemplatableString(gc, "name")
+
+	v.MayHaveMap(gc, "vars", func(vars model.Map) {
+		gt.Vars = p.loadVars(v, vars)
+	})
+
+	groupDefaults := &testDefaults{dir: defaults.dir, timeout: defaults.timeout, env: defaults.env, tags: defaults.tags}
+	if dir, exists, _ := v.MayHaveString(gc, "dir"); exists {
+		groupDefaults.dir = dir
+	}
+
+	if timeout, exists, _ := v.MayHaveDuration(gc, "timeout"); exists {
+		groupDefaults.timeout = timeout
+	}
+
+	if groupEnv, exists := p.loadEnv(v, gc); exists {
+		groupDefaults.env = append(append([]*template.TemplatableStringVar{}, defaults.env...), groupEnv...)
+	}
+
+	if groupTags, exists := p.loadTags(v, gc); exists {
+		groupDefaults.tags = append(append([]string{}, defaults.tags...), groupTags...)
+	}
+
+	gt.Hooks = p.loadHooks(v, gc, groupDefaults)
+	gt.Tests, gt.Groups = p.loadChildren(env, v, gc, groupDefaults)
+
+	return gt
+}
+
+func decodeYAML(in io.Reader, out any) error {
+	return yaml.NewDecoder(in).Decode(out)
 }
 
-func (p *Parser) loadTest(env *model.Env, v *model.Validator, x any) *template.TestTemplate {
+func (p *Parser) loadVars(v *model.Validator, vars model.Map) []*template.TemplatableVar {
+	names := make([]string, 0, len(vars))
+	for name := range vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	tvs := make([]*template.TemplatableVar, 0, len(vars))
+	for _, name := range names {
+		v.InField(name, func() {
+			if !v.MustBeVariableName(name) {
+				return
+			}
+
+			value, _ := v.MustBeTemplatable(vars[name])
+			tvs = append(tvs, &template.TemplatableVar{Name: name, Value: value})
+		})
+	}
+
+	return tvs
+}
+
+func (p *Parser) loadTest(env *model.Env, v *model.Validator, x any, defaults *testDefaults) *template.TestTemplate {
 	tc, ok := v.MustBeMap(x)
 	if !ok {
 		return nil
 	}
 
-	v.MustContainOnly(tc, "name", "command", "stdin", "env", "dir", "expect", "timeout", "teeStdout", "teeStderr")
+	v.MustContainOnly(tc, "name", "command", "stdin", "env", "dir", "expect", "timeout", "teeStdout", "teeStderr", "serial", "tags", "skip", "pending", "focus", "skipIf", "onlyIf", "each", "matrix")
 
 	tt := new(template.TestTemplate)
 	tt.SpecFilename = v.Filename
+	if pos, ok := v.Position(); ok {
+		tt.Line = pos.Line
+	}
 	name, exists, ok := v.MayHaveTemplatableString(tc, "name")
 	if exists {
 		tt.Name = name
 	}
 
-	v.MustHaveSeq(tc, "command", func(seq model.Seq) {
-		command := make([]*model.Templatable[any], 0)
+	tt.Command = p.loadCommand(v, tc)
+
+	v.MayHave(tc, "stdin", func(stdin any) {
+		tt.Stdin, _ = v.MustBeTemplatable(stdin)
+	})
+
+	tt.Env, _ = p.loadEnv(v, tc)
+	tt.DefaultEnv = defaults.env
+
+	if timeout, exists, _ := v.MayHaveDuration(tc, "timeout"); exists {
+		tt.Timeout = timeout
+	} else {
+		tt.Timeout = defaults.timeout
+	}
+
+	v.MayHaveMap(tc, "expect", func(expect model.Map) {
+		tt.StatusMatcher, tt.StdoutMatcher, tt.StderrMatcher = p.loadCommandExpect(env, v, expect)
+	})
+
+	if teeStdout, exists, _ := v.MayHaveBool(tc, "teeStdout"); exists {
+		tt.TeeStdout = teeStdout
+	}
+
+	if teeStderr, exists, _ := v.MayHaveBool(tc, "teeStderr"); exists {
+		tt.TeeStderr = teeStderr
+	}
+
+	if serial, exists, _ := v.MayHaveBool(tc, "serial"); exists {
+		tt.Serial = serial
+	}
+
+	testTags, _ := p.loadTags(v, tc)
+	tt.Tags = append(append([]string{}, defaults.tags...), testTags...)
+
+	tt.SkipReason = p.loadMarker(v, tc, "skip")
+	tt.PendingReason = p.loadMarker(v, tc, "pending")
+	if focus, exists, _ := v.MayHaveBool(tc, "focus"); exists {
+		tt.Focus = focus
+	}
+
+	// TODO: should be templatable?
+	if dir, exists, _ := v.MayHaveString(tc, "dir"); exists {
+		tt.Dir = dir
+	} else {
+		tt.Dir = defaults.dir
+	}
+
+	// probes are run with the settings of the test
+	probeDefaults := &testDefaults{dir: tt.Dir, timeout: tt.Timeout, env: append(append([]*template.TemplatableStringVar{}, tt.DefaultEnv...), tt.Env...)}
+	tt.SkipIf = p.loadConditions(v, tc, "skipIf", probeDefaults)
+	tt.OnlyIf = p.loadConditions(v, tc, "onlyIf", probeDefaults)
+
+	tt.Rows = p.loadRows(v, tc)
+
+	return tt
+}
+
+// loadRows returns variable bindings given by .each (list of rows) or .matrix (cartesian product of lists)
+func (p *Parser) loadRows(v *model.Validator, tc model.Map) []model.Map {
+	_, hasEach := tc["each"]
+	_, hasMatrix := tc["matrix"]
+	if hasEach && hasMatrix {
+		v.AddViolation("should not have both .each and .matrix")
+		return nil
+	}
+
+	var rows []model.Map
+	v.MayHaveSeq(tc, "each", func(each model.Seq) {
+		rows = make([]model.Map, 0, len(each))
+		v.ForInSeq(each, func(i int, x any) bool {
+			row, ok := v.MustBeMap(x)
+			if !ok || !p.mustHaveVariableNames(v, row) {
+				return false
+			}
+			rows = append(rows, row)
+			return true
+		})
+
+		if len(each) == 0 {
+			v.AddViolation("should have one or more rows")
+		}
+	})
+
+	v.MayHaveMap(tc, "matrix", func(matrix model.Map) {
+		if !p.mustHaveVariableNames(v, matrix) {
+			return
+		}
+
+		names := make([]string, 0, len(matrix))
+		for name := range matrix {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		rows = []model.Map{{}}
+		for _, name := range names {
+			var values model.Seq
+			v.InField(name, func() {
+				var ok bool
+				if values, ok = v.MustBeSeq(matrix[name]); ok && len(values) == 0 {
+					v.AddViolation("should have one or more values")
+				}
+			})
+
+			product := make([]model.Map, 0, len(rows)*len(values))
+			for _, row := range rows {
+				for _, value := range values {
+					newRow := model.Map{name: value}
+					for k, x := range row {
+						newRow[k] = x
+					}
+					product = append(product, newRow)
+				}
+			}
+			rows = product
+		}
+	})
+
+	return rows
+}
+
+func (p *Parser) mustHaveVariableNames(v *model.Validator, m model.Map) bool {
+	ok := true
+	for name := range m {
+		v.InField(name, func() {
+			ok = v.MustBeVariableName(name) && ok
+		})
+	}
+
+	return ok
+}
+
+func (p *Parser) loadCommand(v *model.Validator, m model.Map) []*model.Templatable[any] {
+	var command []*model.Templatable[any]
+	v.MustHaveSeq(m, "command", func(seq model.Seq) {
+		command = make([]*model.Templatable[any], 0)
 		v.ForInSeq(seq, func(i int, x any) bool {
 			c, ok := v.MustBeTemplatable(x)
 			command = append(command, c)
 			return ok
 		})
-
-		tt.Command = command
 	})
 
-	v.MayHave(tc, "stdin", func(stdin any) {
-		tt.Stdin, _ = v.MustBeTemplatable(stdin)
-	})
+	return command
+}
 
-	v.MayHaveSeq(tc, "env", func(seq model.Seq) {
-		env := make([]*template.TemplatableStringVar, 0)
+func (p *Parser) loadHooks(v *model.Validator, m model.Map, defaults *testDefaults) template.HooksTemplate {
+	loadHookSeq := func(key string) []*template.HookTemplate {
+		hooks := make([]*template.HookTemplate, 0)
+		v.MayHaveSeq(m, key, func(seq model.Seq) {
+			v.ForInSeq(seq, func(i int, x any) bool {
+				h := p.loadHook(v, x, defaults)
+				hooks = append(hooks, h)
+				return h != nil
+			})
+		})
+		return hooks
+	}
+
+	return template.HooksTemplate{
+		BeforeAll:  loadHookSeq("beforeAll"),
+		BeforeEach: loadHookSeq("beforeEach"),
+		AfterEach:  loadHookSeq("afterEach"),
+		AfterAll:   loadHookSeq("afterAll"),
+	}
+}
+
+func (p *Parser) loadHook(v *model.Validator, x any, defaults *testDefaults) *template.HookTemplate {
+	hc, ok := v.MustBeMap(x)
+	if !ok {
+		return nil
+	}
+
+	v.MustContainOnly(hc, "command", "timeout")
+
+	ht := &template.HookTemplate{Dir: defaults.dir, Env: defaults.env, Timeout: defaults.timeout}
+	ht.Command = p.loadCommand(v, hc)
+	if timeout, exists, _ := v.MayHaveDuration(hc, "timeout"); exists {
+		ht.Timeout = timeout
+	}
+
+	return ht
+}
+
+func (p *Parser) loadEnv(v *model.Validator, m model.Map) ([]*template.TemplatableStringVar, bool) {
+	env := make([]*template.TemplatableStringVar, 0)
+	_, exists, _ := v.MayHaveSeq(m, "env", func(seq model.Seq) {
 		v.ForInSeq(seq, func(i int, x any) bool {
 			m, ok := v.MustBeMap(x)
 			if !ok {
@@ -158,34 +528,126 @@ func (p *Parser) loadTest(env *model.Env, v *model.Validator, x any) *template.T
 			env = append(env, &template.TemplatableStringVar{Name: name, Value: value})
 			return true
 		})
-
-		tt.Env = env
 	})
 
-	if timeout, exists, _ := v.MayHaveDuration(tc, "timeout"); exists {
-		tt.Timeout = timeout
-	}
+	return env, exists
+}
 
-	v.MayHaveMap(tc, "expect", func(expect model.Map) {
-		tt.StatusMatcher, tt.StdoutMatcher, tt.StderrMatcher = p.loadCommandExpect(env, v, expect)
+// loadConditions returns conditions given by a map or a seq of maps
+func (p *Parser) loadConditions(v *model.Validator, m model.Map, key string, defaults *testDefaults) []*template.ConditionTemplate {
+	conditions := make([]*template.ConditionTemplate, 0)
+	v.MayHave(m, key, func(x any) {
+		if cm, ok := v.MayBeMap(x); ok {
+			if c := p.loadCondition(v, cm, defaults); c != nil {
+				conditions = append(conditions, c)
+			}
+			return
+		}
+
+		seq, ok := v.MayBeSeq(x)
+		if !ok {
+			v.AddViolation("should be map or seq, but is %s", model.TypeNameOf(x))
+			return
+		}
+
+		v.ForInSeq(seq, func(i int, x any) bool {
+			cm, ok := v.MustBeMap(x)
+			if !ok {
+				return false
+			}
+			c := p.loadCondition(v, cm, defaults)
+			if c != nil {
+				conditions = append(conditions, c)
+			}
+			return c != nil
+		})
 	})
 
-	if teeStdout, exists, _ := v.MayHaveBool(tc, "teeStdout"); exists {
-		tt.TeeStdout = teeStdout
+	return conditions
+}
+
+func (p *Parser) loadCondition(v *model.Validator, cm model.Map, defaults *testDefaults) *template.ConditionTemplate {
+	_, hasEnv := cm["env"]
+	_, hasCommand := cm["command"]
+	_, hasProbe := cm["probe"]
+	_, hasExpr := cm["expr"]
+	if len(cm) != 1 || !(hasEnv || hasCommand || hasProbe || hasExpr) {
+		v.AddViolation("should have exactly one of .env, .command, .probe and .expr")
+		return nil
 	}
 
-	if teeStderr, exists, _ := v.MayHaveBool(tc, "teeStderr"); exists {
-		tt.TeeStderr = teeStderr
+	c := &template.ConditionTemplate{}
+	ok := false
+	switch {
+	case hasEnv:
+		c.Env, ok = p.mustHaveNonEmptyString(v, cm, "env")
+	case hasCommand:
+		c.Command, ok = p.mustHaveNonEmptyString(v, cm, "command")
+	case hasProbe:
+		v.InField("probe", func() {
+			c.Probe = p.loadHook(v, cm["probe"], defaults)
+		})
+		ok = c.Probe != nil
+	case hasExpr:
+		v.InField("expr", func() {
+			c.Expr, ok = v.MustBeTemplatable(cm["expr"])
+		})
 	}
 
-	// TODO: should be templatable?
-	if dir, exists, _ := v.MayHaveString(tc, "dir"); exists {
-		tt.Dir = dir
-	} else {
-		tt.Dir = v.GetDir()
+	if !ok {
+		return nil
 	}
 
-	return tt
+	return c
+}
+
+func (p *Parser) mustHaveNonEmptyString(v *model.Validator, m model.Map, key string) (string, bool) {
+	s, ok := v.MustHaveString(m, key)
+	if ok && len(s) == 0 {
+		v.InField(key, func() {
+			v.AddViolation("should not be empty string")
+		})
+		return "", false
+	}
+
+	return s, ok
+}
+
+// loadMarker returns the reason given by true or string of m[key], or empty string when it is not given or false
+func (p *Parser) loadMarker(v *model.Validator, m model.Map, key string) string {
+	reason := ""
+	v.MayHave(m, key, func(x any) {
+		if s, ok := v.MayBeString(x); ok {
+			if len(s) == 0 {
+				v.AddViolation("should not be empty string")
+				return
+			}
+			reason = s
+		} else if b, ok := x.(bool); ok {
+			if b {
+				reason = model.NoReasonGiven
+			}
+		} else {
+			v.AddViolation("should be bool or string, but is %s", model.TypeNameOf(x))
+		}
+	})
+
+	return reason
+}
+
+func (p *Parser) loadTags(v *model.Validator, m model.Map) ([]string, bool) {
+	tags := make([]string, 0)
+	_, exists, _ := v.MayHaveSeq(m, "tags", func(seq model.Seq) {
+		v.ForInSeq(seq, func(i int, x any) bool {
+			tag, ok := v.MustBeTagName(x)
+			if ok {
+				tags = append(tags, tag)
+			}
+			return ok
+		})
+	})
+
+	return tags, exists
 }
 
 func (p *Parser) loadCommandExpect(env *model.Env, v *model.Validator, expect model.Map) (*model.Templatable[any], *model.Templatable[any], *model.Templatable[any]) {
@@ -2,6 +2,7 @@ package spec
 
 import (
 	"encoding/json"
+	"errors"
 	"path/filepath"
 	"time"
 
@@ -10,6 +11,7 @@ import (
 	"github.com/autopp/spexec/pkg/matcher/stream"
 	"github.com/autopp/spexec/pkg/model"
 	"github.com/autopp/spexec/pkg/model/template"
+	"github.com/autopp/spexec/pkg/util"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -36,13 +38,14 @@ var _ = Describe("Parser", func() {
 				v, _ := model.NewValidator(filepath.Join("testdata", filename), true)
 				actual, err := p.ParseFile(env, v, filepath.Join("testdata", filename))
 				Expect(err).NotTo(HaveOccurred())
-				Expect(actual).To(MatchAllElementsWithIndex(IndexIdentity, expected))
+				Expect(actual.Tests).To(MatchAllElementsWithIndex(IndexIdentity, expected))
 			},
 
 			Entry("testdata/test.yaml", "test.yaml", Elements{
 				"0": PointTo(MatchAllFields(Fields{
 					"Name":         Equal(model.NewTemplatableFromValue("test_answer")),
 					"SpecFilename": HaveSuffix("testdata/test.yaml"),
+					"Line":         Equal(2),
 					"Command": Equal([]*model.Templatable[any]{
 						model.NewTemplatableFromTemplateValue[any](model.NewTemplateValue("echo", []model.TemplateRef{})),
 						model.NewTemplatableFromTemplateValue[any](model.NewTemplateValue("42", []model.TemplateRef{}))},
@@ -58,12 +61,22 @@ var _ = Describe("Parser", func() {
 					"StderrMatcher": BeNil(),
 					"TeeStdout":     BeFalse(),
 					"TeeStderr":     BeFalse(),
+					"Serial":        BeFalse(),
+					"Tags":          BeEmpty(),
+					"SkipReason":    BeEmpty(),
+					"PendingReason": BeEmpty(),
+					"Focus":         BeFalse(),
+					"SkipIf":        BeEmpty(),
+					"OnlyIf":        BeEmpty(),
+					"Rows":          BeNil(),
+					"DefaultEnv":    BeEmpty(),
 				})),
 			}),
 			Entry("testdata/test.json", "test.json", Elements{
 				"0": PointTo(MatchAllFields(Fields{
 					"Name":         Equal(model.NewTemplatableFromValue("test_answer")),
 					"SpecFilename": HaveSuffix("testdata/test.json"),
+					"Line":         Equal(3),
 					"Command": Equal([]*model.Templatable[any]{
 						model.NewTemplatableFromTemplateValue[any](model.NewTemplateValue("echo", []model.TemplateRef{})),
 						model.NewTemplatableFromTemplateValue[any](model.NewTemplateValue("42", []model.TemplateRef{}))},
@@ -79,10 +92,33 @@ var _ = Describe("Parser", func() {
 					"StderrMatcher": BeNil(),
 					"TeeStdout":     BeFalse(),
 					"TeeStderr":     BeFalse(),
+					"Serial":        BeFalse(),
+					"Tags":          BeEmpty(),
+					"SkipReason":    BeEmpty(),
+					"PendingReason": BeEmpty(),
+					"Focus":         BeFalse(),
+					"SkipIf":        BeEmpty(),
+					"OnlyIf":        BeEmpty(),
+					"Rows":          BeNil(),
+					"DefaultEnv":    BeEmpty(),
 				})),
 			}),
 		)
 
+		DescribeTable("with invalid file",
+			func(filename string, expected string) {
+				v, _ := model.NewValidator(filepath.Join("testdata", filename), true)
+				_, err := p.ParseFile(env, v, filepath.Join("testdata", filename))
+				Expect(err).To(MatchError(expected))
+
+				var ve *model.ValidationError
+				Expect(errors.As(err, &ve)).To(BeTrue())
+				Expect(ve.Violations).To(HaveLen(1))
+			},
+			Entry("testdata/invalid.yaml", "invalid.yaml", "testdata/invalid.yaml:4:5: $.tests[0].timeout: should be positive integer or duration string, but is bool"),
+			Entry("testdata/invalid.json", "invalid.json", "testdata/invalid.json:5:7: $.tests[0].timeout: should be positive integer or duration string, but is bool"),
+		)
+
 		Describe("with no exist file", func() {
 			It("returns err", func() {
 				v, _ := model.NewValidator(filepath.Join("testdata", "unknown.yaml"), true)
@@ -90,6 +126,49 @@ var _ = Describe("Parser", func() {
 				Expect(err).To(HaveOccurred())
 			})
 		})
+
+		Describe("with default timeout and env", func() {
+			It("uses them for tests without timeout and before env of tests", func() {
+				p = NewParser(p.statusMR, p.streamMR, WithDefaultTimeout(5*time.Second), WithDefaultEnv([]util.StringVar{{Name: "LANG", Value: "C"}}))
+				v, _ := model.NewValidator(filepath.Join("testdata", "test.yaml"), true)
+				actual, err := p.ParseFile(env, v, filepath.Join("testdata", "test.yaml"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(actual.Tests[0].Timeout).To(Equal(3 * time.Second))
+				Expect(actual.Tests[0].DefaultEnv).To(Equal([]*template.TemplatableStringVar{
+					{Name: "LANG", Value: model.NewTemplatableFromValue("C")},
+				}))
+				Expect(actual.Tests[0].Env).To(Equal([]*template.TemplatableStringVar{
+					{Name: "ANSWER", Value: model.NewTemplatableFromValue("42")},
+				}))
+			})
+		})
+	})
+
+	Describe("ParseVarsFile()", func() {
+		DescribeTable("with valid file",
+			func(filename string, expected model.Map) {
+				v, _ := model.NewValidator(filepath.Join("testdata", filename), true)
+				Expect(p.ParseVarsFile(v, filepath.Join("testdata", filename))).To(Equal(expected))
+			},
+			Entry("testdata/vars.yaml", "vars.yaml", model.Map{"command": "echo", "answer": 42}),
+			Entry("testdata/vars.json", "vars.json", model.Map{"command": "echo", "answer": json.Number("42")}),
+		)
+
+		Describe("with not map file", func() {
+			It("returns err", func() {
+				v, _ := model.NewValidator(filepath.Join("testdata", "invalid_vars.yaml"), true)
+				_, err := p.ParseVarsFile(v, filepath.Join("testdata", "invalid_vars.yaml"))
+				Expect(err).To(MatchError("$: should be map, but is seq"))
+			})
+		})
+
+		Describe("with no exist file", func() {
+			It("returns err", func() {
+				v, _ := model.NewValidator(filepath.Join("testdata", "unknown.yaml"), true)
+				_, err := p.ParseVarsFile(v, filepath.Join("testdata", "unknown.yaml"))
+				Expect(err).To(HaveOccurred())
+			})
+		})
 	})
 
 	Describe("loadSpec", func() {
@@ -98,7 +177,7 @@ var _ = Describe("Parser", func() {
 				v, _ := model.NewValidator("testdata/spec.yaml", true)
 				actual, err := p.loadSpec(env, v, s)
 				Expect(err).NotTo(HaveOccurred())
-				Expect(actual).To(MatchAllElementsWithIndex(IndexIdentity, Elements{
+				Expect(actual.Tests).To(MatchAllElementsWithIndex(IndexIdentity, Elements{
 					"0": PointTo(MatchAllFields(expected)),
 				}))
 			},
@@ -117,6 +196,7 @@ var _ = Describe("Parser", func() {
 				Fields{
 					"Name":         Equal(model.NewTemplatableFromValue("test_answer")),
 					"SpecFilename": HaveSuffix("/testdata/spec.yaml"),
+					"Line":         BeZero(),
 					"Command": Equal([]*model.Templatable[any]{
 						model.NewTemplatableFromTemplateValue[any](model.NewTemplateValue("echo", []model.TemplateRef{})),
 						model.NewTemplatableFromTemplateValue[any](model.NewTemplateValue("42", []model.TemplateRef{}))},
@@ -132,6 +212,15 @@ var _ = Describe("Parser", func() {
 					"StderrMatcher": BeNil(),
 					"TeeStdout":     BeFalse(),
 					"TeeStderr":     BeFalse(),
+					"Serial":        BeFalse(),
+					"Tags":          BeEmpty(),
+					"SkipReason":    BeEmpty(),
+					"PendingReason": BeEmpty(),
+					"Focus":         BeFalse(),
+					"SkipIf":        BeEmpty(),
+					"OnlyIf":        BeEmpty(),
+					"Rows":          BeNil(),
+					"DefaultEnv":    BeEmpty(),
 				},
 			),
 			Entry("with .spexec",
@@ -150,6 +239,7 @@ var _ = Describe("Parser", func() {
 				Fields{
 					"Name":         Equal(model.NewTemplatableFromValue("test_answer")),
 					"SpecFilename": HaveSuffix("/testdata/spec.yaml"),
+					"Line":         BeZero(),
 					"Command": Equal([]*model.Templatable[any]{
 						model.NewTemplatableFromTemplateValue[any](model.NewTemplateValue("echo", []model.TemplateRef{})),
 						model.NewTemplatableFromTemplateValue[any](model.NewTemplateValue("42", []model.TemplateRef{}))},
@@ -165,10 +255,159 @@ var _ = Describe("Parser", func() {
 					"StderrMatcher": BeNil(),
 					"TeeStdout":     BeFalse(),
 					"TeeStderr":     BeFalse(),
+					"Serial":        BeFalse(),
+					"Tags":          BeEmpty(),
+					"SkipReason":    BeEmpty(),
+					"PendingReason": BeEmpty(),
+					"Focus":         BeFalse(),
+					"SkipIf":        BeEmpty(),
+					"OnlyIf":        BeEmpty(),
+					"Rows":          BeNil(),
+					"DefaultEnv":    BeEmpty(),
 				},
 			),
 		)
 
+		Describe("with .vars", func() {
+			It("returns SpecTemplate with sorted vars", func() {
+				v, _ := model.NewValidator("testdata/spec.yaml", true)
+				actual, err := p.loadSpec(env, v, model.Map{
+					"vars": model.Map{
+						"message": "hello",
+						"command": model.Map{"$": "cmd"},
+					},
+					"tests": model.Seq{
+						model.Map{
+							"command": model.Seq{"echo", "42"},
+						},
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(actual.Vars).To(Equal([]*template.TemplatableVar{
+					{Name: "command", Value: model.NewTemplatableFromTemplateValue[any](model.NewTemplateValue(model.Map{"$": "cmd"}, []model.TemplateRef{model.NewTemplateVar("cmd")}))},
+					{Name: "message", Value: model.NewTemplatableFromTemplateValue[any](model.NewTemplateValue("hello", []model.TemplateRef{}))},
+				}))
+				Expect(actual.Tests).To(HaveLen(1))
+			})
+		})
+
+		Describe("with hooks", func() {
+			It("returns SpecTemplate with hooks which inherit settings of the group", func() {
+				v, _ := model.NewValidator("testdata/spec.yaml", true)
+				actual, err := p.loadSpec(env, v, model.Map{
+					"beforeAll": model.Seq{model.Map{"command": model.Seq{"touch", "db"}}},
+					"groups": model.Seq{
+						model.Map{
+							"name":    "group",
+							"dir":     "/tmp",
+							"env":     model.Seq{model.Map{"name": "ANSWER", "value": "42"}},
+							"timeout": 5,
+							"afterEach": model.Seq{
+								model.Map{"command": model.Seq{"rm", "db"}, "timeout": 1},
+							},
+							"tests": model.Seq{model.Map{"command": model.Seq{"echo"}}},
+						},
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(actual.Hooks.BeforeAll).To(MatchAllElementsWithIndex(IndexIdentity, Elements{
+					"0": PointTo(MatchAllFields(Fields{
+						"Command": Equal([]*model.Templatable[any]{
+							model.NewTemplatableFromTemplateValue[any](model.NewTemplateValue("touch", []model.TemplateRef{})),
+							model.NewTemplatableFromTemplateValue[any](model.NewTemplateValue("db", []model.TemplateRef{})),
+						}),
+						"Dir":     HaveSuffix("/testdata"),
+						"Env":     BeEmpty(),
+						"Timeout": BeZero(),
+					})),
+				}))
+				Expect(actual.Hooks.BeforeEach).To(BeEmpty())
+				Expect(actual.Groups[0].Hooks.AfterEach).To(MatchAllElementsWithIndex(IndexIdentity, Elements{
+					"0": PointTo(MatchAllFields(Fields{
+						"Command": HaveLen(2),
+						"Dir":     Equal("/tmp"),
+						"Env": Equal([]*template.TemplatableStringVar{
+							{Name: "ANSWER", Value: model.NewTemplatableFromValue("42")},
+						}),
+						"Timeout": Equal(1 * time.Second),
+					})),
+				}))
+			})
+		})
+
+		Describe("with .groups", func() {
+			It("returns SpecTemplate with groups which pass settings to their tests", func() {
+				v, _ := model.NewValidator("testdata/spec.yaml", true)
+				actual, err := p.loadSpec(env, v, model.Map{
+					"groups": model.Seq{
+						model.Map{
+							"name":    "outer",
+							"env":     model.Seq{model.Map{"name": "OUTER", "value": "1"}},
+							"dir":     "/tmp",
+							"timeout": 5,
+							"tags":    model.Seq{"slow"},
+							"groups": model.Seq{
+								model.Map{
+									"name":    "inner",
+									"vars":    model.Map{"message": "hello"},
+									"env":     model.Seq{model.Map{"name": "INNER", "value": "2"}},
+									"timeout": 3,
+									"tests": model.Seq{
+										model.Map{
+											"command": model.Seq{"echo"},
+											"env":     model.Seq{model.Map{"name": "TEST", "value": "3"}},
+											"tags":    model.Seq{"network"},
+										},
+										model.Map{
+											"command": model.Seq{"echo"},
+											"dir":     "/etc",
+											"timeout": 1,
+										},
+									},
+								},
+							},
+						},
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(actual.Tests).To(BeEmpty())
+				Expect(actual.Groups).To(HaveLen(1))
+
+				outer := actual.Groups[0]
+				Expect(outer.Name).To(Equal(model.NewTemplatableFromValue("outer")))
+				Expect(outer.Tests).To(BeEmpty())
+				Expect(outer.Groups).To(HaveLen(1))
+
+				inner := outer.Groups[0]
+				Expect(inner.Name).To(Equal(model.NewTemplatableFromValue("inner")))
+				Expect(inner.Vars).To(HaveLen(1))
+				Expect(inner.Tests).To(MatchAllElementsWithIndex(IndexIdentity, Elements{
+					"0": PointTo(MatchFields(IgnoreExtras, Fields{
+						"Dir":     Equal("/tmp"),
+						"Timeout": Equal(3 * time.Second),
+						"DefaultEnv": Equal([]*template.TemplatableStringVar{
+							{Name: "OUTER", Value: model.NewTemplatableFromValue("1")},
+							{Name: "INNER", Value: model.NewTemplatableFromValue("2")},
+						}),
+						"Env": Equal([]*template.TemplatableStringVar{
+							{Name: "TEST", Value: model.NewTemplatableFromValue("3")},
+						}),
+						"Tags": Equal([]string{"slow", "network"}),
+					})),
+					"1": PointTo(MatchFields(IgnoreExtras, Fields{
+						"Dir":     Equal("/etc"),
+						"Timeout": Equal(1 * time.Second),
+						"DefaultEnv": Equal([]*template.TemplatableStringVar{
+							{Name: "OUTER", Value: model.NewTemplatableFromValue("1")},
+							{Name: "INNER", Value: model.NewTemplatableFromValue("2")},
+						}),
+						"Env":  BeEmpty(),
+						"Tags": Equal([]string{"slow"}),
+					})),
+				}))
+			})
+		})
+
 		DescribeTable("failure cases",
 			func(s any, expectedErr string) {
 				v, _ := model.NewValidator("testdata/spec.yaml", true)
@@ -217,6 +456,55 @@ var _ = Describe("Parser", func() {
 				},
 				`$.tests: should be seq, but is map`,
 			),
+			Entry("with invalid variable name",
+				model.Map{
+					"vars": model.Map{"1st": "hello"},
+					"tests": model.Seq{
+						model.Map{
+							"command": model.Seq{"echo", "42"},
+						},
+					},
+				},
+				`$.vars.1st: variable name should be match to /^[_a-zA-Z]\w*$/`,
+			),
+			Entry("with group without name",
+				model.Map{
+					"groups": model.Seq{
+						model.Map{
+							"tests": model.Seq{
+								model.Map{
+									"command": model.Seq{"echo", "42"},
+								},
+							},
+						},
+					},
+				},
+				`$.groups[0]: should have .name as templatable string`,
+			),
+			Entry("with neither .tests nor .groups",
+				model.Map{
+					"spexec": "v0",
+				},
+				`$: should have .tests as seq`,
+			),
+			Entry("with hook without command",
+				model.Map{
+					"beforeEach": model.Seq{model.Map{"timeout": 1}},
+					"tests":      model.Seq{},
+				},
+				`$.beforeEach[0]: should have .command as seq`,
+			),
+			Entry("with invalid .vars",
+				model.Map{
+					"vars": model.Seq{"hello"},
+					"tests": model.Seq{
+						model.Map{
+							"command": model.Seq{"echo", "42"},
+						},
+					},
+				},
+				`$.vars: should be map, but is seq`,
+			),
 			Entry("with not map",
 				model.Seq{
 					model.Map{
@@ -237,11 +525,48 @@ var _ = Describe("Parser", func() {
 		)
 	})
 
+	Describe("loadRows", func() {
+		DescribeTable("success cases",
+			func(test model.Map, expected []model.Map) {
+				v, _ := model.NewValidator("testdata/spec.yaml", true)
+				actual := p.loadRows(v, test)
+				Expect(v.Error()).NotTo(HaveOccurred())
+				Expect(actual).To(Equal(expected))
+			},
+			Entry("without .each nor .matrix", model.Map{}, nil),
+			Entry("with .each",
+				model.Map{"each": model.Seq{model.Map{"a": 1, "b": 2}, model.Map{"a": 3, "b": 4}}},
+				[]model.Map{{"a": 1, "b": 2}, {"a": 3, "b": 4}},
+			),
+			Entry("with .matrix",
+				model.Map{"matrix": model.Map{"b": model.Seq{"x", "y"}, "a": model.Seq{1, 2}}},
+				[]model.Map{{"a": 1, "b": "x"}, {"a": 1, "b": "y"}, {"a": 2, "b": "x"}, {"a": 2, "b": "y"}},
+			),
+		)
+
+		DescribeTable("failure cases",
+			func(test model.Map, expectedErr string) {
+				v, _ := model.NewValidator("testdata/spec.yaml", true)
+				p.loadRows(v, test)
+				Expect(v.Error()).To(MatchError(expectedErr))
+			},
+			Entry("with both .each and .matrix",
+				model.Map{"each": model.Seq{model.Map{"a": 1}}, "matrix": model.Map{"a": model.Seq{1}}},
+				"$: should not have both .each and .matrix",
+			),
+			Entry("with empty .each", model.Map{"each": model.Seq{}}, "$.each: should have one or more rows"),
+			Entry("with not map row", model.Map{"each": model.Seq{42}}, "$.each[0]: should be map, but is int"),
+			Entry("with invalid variable name", model.Map{"each": model.Seq{model.Map{"1st": 1}}}, "$.each[0].1st: variable name should be match to /^[_a-zA-Z]\\w*$/"),
+			Entry("with not seq values of .matrix", model.Map{"matrix": model.Map{"a": 1}}, "$.matrix.a: should be seq, but is int"),
+			Entry("with empty values of .matrix", model.Map{"matrix": model.Map{"a": model.Seq{}}}, "$.matrix.a: should have one or more values"),
+		)
+	})
+
 	Describe("loadTest", func() {
 		DescribeTable("success cases",
 			func(test any, expected Fields) {
 				v, _ := model.NewValidator("testdata/spec.yaml", true)
-				actual := p.loadTest(env, v, test)
+				actual := p.loadTest(env, v, test, &testDefaults{dir: v.GetDir()})
 				Expect(v.Error()).NotTo(HaveOccurred())
 				Expect(actual).To(PointTo(MatchAllFields(expected)))
 			},
@@ -256,6 +581,7 @@ var _ = Describe("Parser", func() {
 				Fields{
 					"Name":         Equal(model.NewTemplatableFromValue("test_answer")),
 					"SpecFilename": HaveSuffix("/testdata/spec.yaml"),
+					"Line":         BeZero(),
 					"Command": Equal([]*model.Templatable[any]{
 						model.NewTemplatableFromTemplateValue[any](model.NewTemplateValue("echo", []model.TemplateRef{})),
 						model.NewTemplatableFromTemplateValue[any](model.NewTemplateValue("42", []model.TemplateRef{}))},
@@ -271,6 +597,15 @@ var _ = Describe("Parser", func() {
 					"StderrMatcher": BeNil(),
 					"TeeStdout":     BeFalse(),
 					"TeeStderr":     BeFalse(),
+					"Serial":        BeFalse(),
+					"Tags":          BeEmpty(),
+					"SkipReason":    BeEmpty(),
+					"PendingReason": BeEmpty(),
+					"Focus":         BeFalse(),
+					"SkipIf":        BeEmpty(),
+					"OnlyIf":        BeEmpty(),
+					"Rows":          BeNil(),
+					"DefaultEnv":    BeEmpty(),
 				},
 			),
 			Entry("with dir",
@@ -285,6 +620,7 @@ var _ = Describe("Parser", func() {
 				Fields{
 					"Name":         Equal(model.NewTemplatableFromValue("test_answer")),
 					"SpecFilename": HaveSuffix("/testdata/spec.yaml"),
+					"Line":         BeZero(),
 					"Command": Equal([]*model.Templatable[any]{
 						model.NewTemplatableFromTemplateValue[any](model.NewTemplateValue("echo", []model.TemplateRef{})),
 						model.NewTemplatableFromTemplateValue[any](model.NewTemplateValue("42", []model.TemplateRef{}))},
@@ -300,6 +636,15 @@ var _ = Describe("Parser", func() {
 					"StderrMatcher": BeNil(),
 					"TeeStdout":     BeFalse(),
 					"TeeStderr":     BeFalse(),
+					"Serial":        BeFalse(),
+					"Tags":          BeEmpty(),
+					"SkipReason":    BeEmpty(),
+					"PendingReason": BeEmpty(),
+					"Focus":         BeFalse(),
+					"SkipIf":        BeEmpty(),
+					"OnlyIf":        BeEmpty(),
+					"Rows":          BeNil(),
+					"DefaultEnv":    BeEmpty(),
 				},
 			),
 			Entry("with matcher",
@@ -312,6 +657,7 @@ var _ = Describe("Parser", func() {
 				Fields{
 					"Name":         Equal(model.NewTemplatableFromValue("test_answer")),
 					"SpecFilename": HaveSuffix("/testdata/spec.yaml"),
+					"Line":         BeZero(),
 					"Command": Equal([]*model.Templatable[any]{
 						model.NewTemplatableFromTemplateValue[any](model.NewTemplateValue("echo", []model.TemplateRef{})),
 						model.NewTemplatableFromTemplateValue[any](model.NewTemplateValue("42", []model.TemplateRef{}))},
@@ -325,6 +671,15 @@ var _ = Describe("Parser", func() {
 					"StderrMatcher": BeNil(),
 					"TeeStdout":     BeFalse(),
 					"TeeStderr":     BeFalse(),
+					"Serial":        BeFalse(),
+					"Tags":          BeEmpty(),
+					"SkipReason":    BeEmpty(),
+					"PendingReason": BeEmpty(),
+					"Focus":         BeFalse(),
+					"SkipIf":        BeEmpty(),
+					"OnlyIf":        BeEmpty(),
+					"Rows":          BeNil(),
+					"DefaultEnv":    BeEmpty(),
 				},
 			),
 			Entry("with TeeStdout",
@@ -337,6 +692,7 @@ var _ = Describe("Parser", func() {
 				Fields{
 					"Name":         Equal(model.NewTemplatableFromValue("test_answer")),
 					"SpecFilename": HaveSuffix("/testdata/spec.yaml"),
+					"Line":         BeZero(),
 					"Command": Equal([]*model.Templatable[any]{
 						model.NewTemplatableFromTemplateValue[any](model.NewTemplateValue("echo", []model.TemplateRef{})),
 						model.NewTemplatableFromTemplateValue[any](model.NewTemplateValue("42", []model.TemplateRef{}))},
@@ -350,6 +706,15 @@ var _ = Describe("Parser", func() {
 					"StderrMatcher": BeNil(),
 					"TeeStdout":     BeTrue(),
 					"TeeStderr":     BeFalse(),
+					"Serial":        BeFalse(),
+					"Tags":          BeEmpty(),
+					"SkipReason":    BeEmpty(),
+					"PendingReason": BeEmpty(),
+					"Focus":         BeFalse(),
+					"SkipIf":        BeEmpty(),
+					"OnlyIf":        BeEmpty(),
+					"Rows":          BeNil(),
+					"DefaultEnv":    BeEmpty(),
 				},
 			),
 			Entry("with TeeStderr",
@@ -362,6 +727,7 @@ var _ = Describe("Parser", func() {
 				Fields{
 					"Name":         Equal(model.NewTemplatableFromValue("test_answer")),
 					"SpecFilename": HaveSuffix("/testdata/spec.yaml"),
+					"Line":         BeZero(),
 					"Command": Equal([]*model.Templatable[any]{
 						model.NewTemplatableFromTemplateValue[any](model.NewTemplateValue("echo", []model.TemplateRef{})),
 						model.NewTemplatableFromTemplateValue[any](model.NewTemplateValue("42", []model.TemplateRef{}))},
@@ -375,6 +741,117 @@ var _ = Describe("Parser", func() {
 					"StderrMatcher": BeNil(),
 					"TeeStdout":     BeFalse(),
 					"TeeStderr":     BeTrue(),
+					"Serial":        BeFalse(),
+					"Tags":          BeEmpty(),
+					"SkipReason":    BeEmpty(),
+					"PendingReason": BeEmpty(),
+					"Focus":         BeFalse(),
+					"SkipIf":        BeEmpty(),
+					"OnlyIf":        BeEmpty(),
+					"Rows":          BeNil(),
+					"DefaultEnv":    BeEmpty(),
+				},
+			),
+			Entry("with serial",
+				model.Map{
+					"name":    "test_answer",
+					"command": model.Seq{"echo", "42"},
+					"serial":  true,
+				},
+				Fields{
+					"Name":         Equal(model.NewTemplatableFromValue("test_answer")),
+					"SpecFilename": HaveSuffix("/testdata/spec.yaml"),
+					"Line":         BeZero(),
+					"Command": Equal([]*model.Templatable[any]{
+						model.NewTemplatableFromTemplateValue[any](model.NewTemplateValue("echo", []model.TemplateRef{})),
+						model.NewTemplatableFromTemplateValue[any](model.NewTemplateValue("42", []model.TemplateRef{}))},
+					),
+					"Dir":           HaveSuffix("/testdata"),
+					"Stdin":         BeNil(),
+					"Env":           BeEmpty(),
+					"Timeout":       Equal(time.Duration(0)),
+					"StatusMatcher": BeNil(),
+					"StdoutMatcher": BeNil(),
+					"StderrMatcher": BeNil(),
+					"TeeStdout":     BeFalse(),
+					"TeeStderr":     BeFalse(),
+					"Serial":        BeTrue(),
+					"Tags":          BeEmpty(),
+					"SkipReason":    BeEmpty(),
+					"PendingReason": BeEmpty(),
+					"Focus":         BeFalse(),
+					"SkipIf":        BeEmpty(),
+					"OnlyIf":        BeEmpty(),
+					"Rows":          BeNil(),
+					"DefaultEnv":    BeEmpty(),
+				},
+			),
+		)
+
+		DescribeTable("with markers",
+			func(markers model.Map, expected Fields) {
+				test := model.Map{"command": model.Seq{"echo", "42"}}
+				for k, x := range markers {
+					test[k] = x
+				}
+				v, _ := model.NewValidator("testdata/spec.yaml", true)
+				actual := p.loadTest(env, v, test, &testDefaults{dir: v.GetDir()})
+				Expect(v.Error()).NotTo(HaveOccurred())
+				Expect(actual).To(PointTo(MatchFields(IgnoreExtras, expected)))
+			},
+			Entry("with skip reason",
+				model.Map{"skip": "not ready"},
+				Fields{"SkipReason": Equal("not ready"), "PendingReason": BeEmpty(), "Focus": BeFalse()},
+			),
+			Entry("with skip true",
+				model.Map{"skip": true},
+				Fields{"SkipReason": Equal(model.NoReasonGiven), "PendingReason": BeEmpty(), "Focus": BeFalse()},
+			),
+			Entry("with skip false",
+				model.Map{"skip": false},
+				Fields{"SkipReason": BeEmpty(), "PendingReason": BeEmpty(), "Focus": BeFalse()},
+			),
+			Entry("with pending true",
+				model.Map{"pending": true},
+				Fields{"SkipReason": BeEmpty(), "PendingReason": Equal(model.NoReasonGiven), "Focus": BeFalse()},
+			),
+			Entry("with focus",
+				model.Map{"focus": true},
+				Fields{"SkipReason": BeEmpty(), "PendingReason": BeEmpty(), "Focus": BeTrue()},
+			),
+		)
+
+		DescribeTable("with conditions",
+			func(conditions model.Map, expectedSkipIf, expectedOnlyIf []*template.ConditionTemplate) {
+				test := model.Map{"command": model.Seq{"echo", "42"}, "timeout": 3}
+				for k, x := range conditions {
+					test[k] = x
+				}
+				v, _ := model.NewValidator("testdata/spec.yaml", true)
+				actual := p.loadTest(env, v, test, &testDefaults{dir: "/tmp"})
+				Expect(v.Error()).NotTo(HaveOccurred())
+				Expect(actual.SkipIf).To(Equal(expectedSkipIf))
+				Expect(actual.OnlyIf).To(Equal(expectedOnlyIf))
+			},
+			Entry("with map",
+				model.Map{"skipIf": model.Map{"env": "CI"}, "onlyIf": model.Map{"command": "docker"}},
+				[]*template.ConditionTemplate{{Env: "CI"}},
+				[]*template.ConditionTemplate{{Command: "docker"}},
+			),
+			Entry("with seq",
+				model.Map{"onlyIf": model.Seq{
+					model.Map{"probe": model.Map{"command": model.Seq{"true"}}},
+					model.Map{"expr": true},
+				}},
+				[]*template.ConditionTemplate{},
+				[]*template.ConditionTemplate{
+					{Probe: &template.HookTemplate{
+						Command: []*model.Templatable[any]{model.NewTemplatableFromTemplateValue[any](model.NewTemplateValue("true", []model.TemplateRef{}))},
+						Dir:     "/tmp",
+						Env:     []*template.TemplatableStringVar{},
+						Timeout: 3 * time.Second,
+					}},
+					{Expr: model.NewTemplatableFromTemplateValue[any](model.NewTemplateValue(true, []model.TemplateRef{}))},
 				},
 			),
 		)
@@ -382,7 +859,7 @@ var _ = Describe("Parser", func() {
 		DescribeTable("failure cases",
 			func(test any, expectedErr string) {
 				v, _ := model.NewValidator("testdata/spec.yaml", true)
-				p.loadTest(env, v, test)
+				p.loadTest(env, v, test, &testDefaults{dir: v.GetDir()})
 				Expect(v.Error()).To(MatchError(expectedErr))
 			},
 			Entry("with not map", 42, "$: should be map, but is int"),
@@ -427,6 +904,55 @@ var _ = Describe("Parser", func() {
 				},
 				"$.teeStderr: should be bool, but is int",
 			),
+			Entry("with invalid skip",
+				model.Map{
+					"command": model.Seq{"echo", "42"},
+					"skip":    42,
+				},
+				"$.skip: should be bool or string, but is int",
+			),
+			Entry("with empty pending reason",
+				model.Map{
+					"command": model.Seq{"echo", "42"},
+					"pending": "",
+				},
+				"$.pending: should not be empty string",
+			),
+			Entry("with invalid focus",
+				model.Map{
+					"command": model.Seq{"echo", "42"},
+					"focus":   "yes",
+				},
+				"$.focus: should be bool, but is string",
+			),
+			Entry("with invalid skipIf",
+				model.Map{
+					"command": model.Seq{"echo", "42"},
+					"skipIf":  42,
+				},
+				"$.skipIf: should be map or seq, but is int",
+			),
+			Entry("with multiple conditions in one map",
+				model.Map{
+					"command": model.Seq{"echo", "42"},
+					"onlyIf":  model.Seq{model.Map{"env": "CI", "command": "docker"}},
+				},
+				"$.onlyIf[0]: should have exactly one of .env, .command, .probe and .expr",
+			),
+			Entry("with empty env name",
+				model.Map{
+					"command": model.Seq{"echo", "42"},
+					"skipIf":  model.Map{"env": ""},
+				},
+				"$.skipIf.env: should not be empty string",
+			),
+			Entry("with invalid tag",
+				model.Map{
+					"command": model.Seq{"echo", "42"},
+					"tags":    model.Seq{"slow", "not"},
+				},
+				"$.tags[1]: tag should be match to /^[\\w.:/-]+$/ and not be and, or, not",
+			),
 		)
 	})
 
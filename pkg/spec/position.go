@@ -0,0 +1,129 @@
+// Copyright (C) 2021-2023	 Akira Tanimura (@autopp)
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spec
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/autopp/spexec/pkg/model"
+	"gopkg.in/yaml.v3"
+)
+
+// yamlPositions returns the positions of all paths in the YAML document.
+// The position of a map entry is the one of its key.
+func yamlPositions(node *yaml.Node) map[string]model.Position {
+	positions := make(map[string]model.Position)
+	if node.Kind == yaml.DocumentNode && len(node.Content) != 0 {
+		node = node.Content[0]
+	}
+	positions["$"] = model.Position{Line: node.Line, Column: node.Column}
+	collectYAMLPositions(positions, "$", node)
+
+	return positions
+}
+
+func collectYAMLPositions(positions map[string]model.Position, path string, node *yaml.Node) {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			childPath := path + "." + key.Value
+			positions[childPath] = model.Position{Line: key.Line, Column: key.Column}
+			collectYAMLPositions(positions, childPath, value)
+		}
+	case yaml.SequenceNode:
+		for i, item := range node.Content {
+			childPath := path + fmt.Sprintf("[%d]", i)
+			positions[childPath] = model.Position{Line: item.Line, Column: item.Column}
+			collectYAMLPositions(positions, childPath, item)
+		}
+	}
+}
+
+// jsonPositions returns the positions of all paths in the JSON document.
+// The position of an object member is the one of its key.
+func jsonPositions(b []byte) (map[string]model.Position, error) {
+	lineStarts := []int{0}
+	for i, c := range b {
+		if c == '\n' {
+			lineStarts = append(lineStarts, i+1)
+		}
+	}
+	toPosition := func(offset int) model.Position {
+		line := sort.Search(len(lineStarts), func(i int) bool { return lineStarts[i] > offset })
+		return model.Position{Line: line, Column: offset - lineStarts[line-1] + 1}
+	}
+
+	positions := make(map[string]model.Position)
+	d := json.NewDecoder(bytes.NewReader(b))
+	var collect func(path string) error
+	collect = func(path string) error {
+		positions[path] = toPosition(skipJSONSeparators(b, int(d.InputOffset())))
+		t, err := d.Token()
+		if err != nil {
+			return err
+		}
+
+		switch t {
+		case json.Delim('{'):
+			for d.More() {
+				keyPosition := toPosition(skipJSONSeparators(b, int(d.InputOffset())))
+				key, err := d.Token()
+				if err != nil {
+					return err
+				}
+				childPath := fmt.Sprintf("%s.%s", path, key)
+				if err := collect(childPath); err != nil {
+					return err
+				}
+				positions[childPath] = keyPosition
+			}
+		case json.Delim('['):
+			for i := 0; d.More(); i++ {
+				if err := collect(fmt.Sprintf("%s[%d]", path, i)); err != nil {
+					return err
+				}
+			}
+		default:
+			return nil
+		}
+
+		// consume the closing delimiter
+		_, err = d.Token()
+		return err
+	}
+
+	if err := collect("$"); err != nil {
+		return nil, err
+	}
+
+	return positions, nil
+}
+
+// skipJSONSeparators returns the offset of the next token from offset
+func skipJSONSeparators(b []byte, offset int) int {
+	for offset < len(b) && bytes.IndexByte([]byte(" \t\r\n,:"), b[offset]) >= 0 {
+		offset++
+	}
+
+	return offset
+}
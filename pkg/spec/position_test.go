@@ -0,0 +1,55 @@
+package spec
+
+import (
+	"github.com/autopp/spexec/pkg/model"
+	"gopkg.in/yaml.v3"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("yamlPositions()", func() {
+	It("returns the positions of map keys and seq items", func() {
+		var node yaml.Node
+		Expect(yaml.Unmarshal([]byte(`tests:
+  - name: test
+    command:
+      - echo
+`), &node)).To(Succeed())
+
+		Expect(yamlPositions(&node)).To(Equal(map[string]model.Position{
+			"$":                     {Line: 1, Column: 1},
+			"$.tests":               {Line: 1, Column: 1},
+			"$.tests[0]":            {Line: 2, Column: 5},
+			"$.tests[0].name":       {Line: 2, Column: 5},
+			"$.tests[0].command":    {Line: 3, Column: 5},
+			"$.tests[0].command[0]": {Line: 4, Column: 9},
+		}))
+	})
+})
+
+var _ = Describe("jsonPositions()", func() {
+	It("returns the positions of object keys and array elements", func() {
+		positions, err := jsonPositions([]byte(`{
+  "tests": [
+    {"name": "test", "command": ["echo", 42]}
+  ]
+}`))
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(positions).To(Equal(map[string]model.Position{
+			"$":                     {Line: 1, Column: 1},
+			"$.tests":               {Line: 2, Column: 3},
+			"$.tests[0]":            {Line: 3, Column: 5},
+			"$.tests[0].name":       {Line: 3, Column: 6},
+			"$.tests[0].command":    {Line: 3, Column: 22},
+			"$.tests[0].command[0]": {Line: 3, Column: 34},
+			"$.tests[0].command[1]": {Line: 3, Column: 42},
+		}))
+	})
+
+	It("returns error for invalid JSON", func() {
+		_, err := jsonPositions([]byte(`{"tests": [}`))
+		Expect(err).To(HaveOccurred())
+	})
+})
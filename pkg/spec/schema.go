@@ -0,0 +1,167 @@
+// Copyright (C) 2021-2023	 Akira Tanimura (@autopp)
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spec
+
+import (
+	"github.com/autopp/spexec/pkg/model"
+)
+
+// schemaID is the identifier of the JSON Schema of spec
+const schemaID = "https://github.com/autopp/spexec/spec.schema.json"
+
+func schemaRef(name string) model.Map {
+	return model.Map{"$ref": "#/$defs/" + name}
+}
+
+func seqOf(items model.Map) model.Map {
+	return model.Map{"type": "array", "items": items}
+}
+
+func objectOf(properties model.Map, required ...any) model.Map {
+	schema := model.Map{"type": "object", "properties": properties, "additionalProperties": false}
+	if len(required) != 0 {
+		schema["required"] = required
+	}
+
+	return schema
+}
+
+// Schema returns the JSON Schema of spec file including the matchers of the registries
+func (p *Parser) Schema() model.Map {
+	variable := schemaRef("variable")
+	templatable := func(schema model.Map) model.Map {
+		return model.Map{"anyOf": []any{schema, variable, schemaRef("templateText")}}
+	}
+	hooks := model.Map{
+		"beforeAll":  seqOf(schemaRef("hook")),
+		"beforeEach": seqOf(schemaRef("hook")),
+		"afterEach":  seqOf(schemaRef("hook")),
+		"afterAll":   seqOf(schemaRef("hook")),
+	}
+	// either tests or groups are required as parser does
+	requireChildren := func(schema model.Map) model.Map {
+		schema["anyOf"] = []any{model.Map{"required": []any{"tests"}}, model.Map{"required": []any{"groups"}}}
+		return schema
+	}
+	withHooks := func(properties model.Map) model.Map {
+		for k, v := range hooks {
+			properties[k] = v
+		}
+		return properties
+	}
+
+	defs := model.Map{
+		"variable":          objectOf(model.Map{"$": model.Map{"type": "string", "pattern": `^[_a-zA-Z]\w*$`}}, "$"),
+		"templateText":      objectOf(model.Map{"$t": model.Map{"type": "string"}}, "$t"),
+		"templatableString": templatable(model.Map{"type": "string"}),
+		"duration": model.Map{
+			"anyOf": []any{model.Map{"type": "integer", "minimum": 1}, model.Map{"type": "string"}},
+		},
+		"vars": model.Map{
+			"type":                 "object",
+			"propertyNames":        model.Map{"pattern": `^[_a-zA-Z]\w*$`},
+			"additionalProperties": true,
+		},
+		"stringExpr": model.Map{
+			"anyOf": []any{
+				schemaRef("templatableString"),
+				objectOf(model.Map{"type": model.Map{"const": "env"}, "name": model.Map{"type": "string"}}, "type", "name"),
+				objectOf(model.Map{
+					"type":   model.Map{"const": "file"},
+					"format": model.Map{"enum": []any{"raw", "yaml"}},
+					"value":  model.Map{},
+				}, "type", "value"),
+			},
+		},
+		"command": seqOf(schemaRef("stringExpr")),
+		"stdin": model.Map{
+			"anyOf": []any{
+				schemaRef("templatableString"),
+				objectOf(model.Map{"format": model.Map{"enum": []any{"yaml"}}, "value": model.Map{}}, "format", "value"),
+			},
+		},
+		"env":  seqOf(objectOf(model.Map{"name": model.Map{"type": "string"}, "value": schemaRef("templatableString")}, "name", "value")),
+		"tags": seqOf(model.Map{"type": "string"}),
+		"marker": model.Map{
+			"anyOf": []any{model.Map{"type": "boolean"}, model.Map{"type": "string", "minLength": 1}},
+		},
+		"hook": objectOf(model.Map{"command": schemaRef("command"), "timeout": schemaRef("duration")}, "command"),
+		"condition": model.Map{
+			"anyOf": []any{
+				objectOf(model.Map{"env": model.Map{"type": "string", "minLength": 1}}, "env"),
+				objectOf(model.Map{"command": model.Map{"type": "string", "minLength": 1}}, "command"),
+				objectOf(model.Map{"probe": schemaRef("hook")}, "probe"),
+				objectOf(model.Map{"expr": model.Map{}}, "expr"),
+			},
+		},
+		"conditions": model.Map{
+			"anyOf": []any{schemaRef("condition"), seqOf(schemaRef("condition"))},
+		},
+		"expect": objectOf(model.Map{
+			"status": model.Map{"anyOf": []any{p.statusMR.SchemaRef(), variable}},
+			"stdout": model.Map{"anyOf": []any{p.streamMR.SchemaRef(), variable}},
+			"stderr": model.Map{"anyOf": []any{p.streamMR.SchemaRef(), variable}},
+		}),
+		p.statusMR.SchemaName(): p.statusMR.Schema(variable),
+		p.streamMR.SchemaName(): p.streamMR.Schema(variable),
+		"test": objectOf(model.Map{
+			"name":      schemaRef("templatableString"),
+			"command":   schemaRef("command"),
+			"stdin":     schemaRef("stdin"),
+			"env":       schemaRef("env"),
+			"dir":       model.Map{"type": "string"},
+			"expect":    schemaRef("expect"),
+			"timeout":   schemaRef("duration"),
+			"teeStdout": model.Map{"type": "boolean"},
+			"teeStderr": model.Map{"type": "boolean"},
+			"serial":    model.Map{"type": "boolean"},
+			"tags":      schemaRef("tags"),
+			"skip":      schemaRef("marker"),
+			"pending":   schemaRef("marker"),
+			"focus":     model.Map{"type": "boolean"},
+			"skipIf":    schemaRef("conditions"),
+			"onlyIf":    schemaRef("conditions"),
+			"each":      model.Map{"type": "array", "items": schemaRef("vars"), "minItems": 1},
+			"matrix": model.Map{
+				"type":                 "object",
+				"propertyNames":        model.Map{"pattern": `^[_a-zA-Z]\w*$`},
+				"additionalProperties": model.Map{"type": "array", "minItems": 1},
+			},
+		}, "command"),
+		"group": requireChildren(objectOf(withHooks(model.Map{
+			"name":    schemaRef("templatableString"),
+			"vars":    schemaRef("vars"),
+			"env":     schemaRef("env"),
+			"dir":     model.Map{"type": "string"},
+			"timeout": schemaRef("duration"),
+			"tags":    schemaRef("tags"),
+			"tests":   seqOf(schemaRef("test")),
+			"groups":  seqOf(schemaRef("group")),
+		}), "name")),
+	}
+
+	schema := requireChildren(objectOf(withHooks(model.Map{
+		"spexec": model.Map{"const": "v0"},
+		"vars":   schemaRef("vars"),
+		"tests":  seqOf(schemaRef("test")),
+		"groups": seqOf(schemaRef("group")),
+	})))
+	schema["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	schema["$id"] = schemaID
+	schema["title"] = "spexec spec"
+	schema["$defs"] = defs
+
+	return schema
+}
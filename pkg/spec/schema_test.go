@@ -0,0 +1,89 @@
+package spec
+
+import (
+	"strings"
+
+	"github.com/autopp/spexec/pkg/matcher"
+	"github.com/autopp/spexec/pkg/matcher/status"
+	"github.com/autopp/spexec/pkg/matcher/stream"
+	"github.com/autopp/spexec/pkg/model"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Parser", func() {
+	Describe("Schema()", func() {
+		var schema model.Map
+
+		JustBeforeEach(func() {
+			streamMR := stream.NewStreamMatcherRegistryWithBuiltins()
+			streamMR.Add("custom", func(v *model.Validator, r *matcher.StreamMatcherRegistry, x any) model.StreamMatcher {
+				return nil
+			}, matcher.WithParamSchema(model.Map{"type": "string"}))
+			schema = NewParser(status.NewStatusMatcherRegistryWithBuiltins(), streamMR).Schema()
+		})
+
+		It("contains the matchers of the registries", func() {
+			defs := schema["$defs"].(model.Map)
+			streamProperties := defs["streamMatcher"].(model.Map)["anyOf"].([]any)[0].(model.Map)["properties"].(model.Map)
+			Expect(streamProperties).To(HaveKey("custom"))
+			Expect(streamProperties).To(HaveKey("eq"))
+			statusProperties := defs["statusMatcher"].(model.Map)["anyOf"].([]any)[0].(model.Map)["properties"].(model.Map)
+			Expect(statusProperties).To(HaveKey("success"))
+		})
+
+		It("has only the references to the definitions in it", func() {
+			defs := schema["$defs"].(model.Map)
+			var walk func(x any)
+			walk = func(x any) {
+				switch x := x.(type) {
+				case model.Map:
+					if ref, ok := x["$ref"]; ok {
+						Expect(defs).To(HaveKey(strings.TrimPrefix(ref.(string), "#/$defs/")))
+					}
+					for _, v := range x {
+						walk(v)
+					}
+				case []any:
+					for _, v := range x {
+						walk(v)
+					}
+				}
+			}
+			walk(schema)
+		})
+
+		It("accepts templatable strings in command and stdin", func() {
+			defs := schema["$defs"].(model.Map)
+			Expect(defs["stringExpr"].(model.Map)["anyOf"]).To(ContainElement(schemaRef("templatableString")))
+			Expect(defs["stdin"].(model.Map)["anyOf"]).To(ContainElement(schemaRef("templatableString")))
+		})
+
+		It("requires tests or groups in spec and group", func() {
+			requireChildren := []any{model.Map{"required": []any{"tests"}}, model.Map{"required": []any{"groups"}}}
+			Expect(schema["anyOf"]).To(Equal(requireChildren))
+			Expect(schema["$defs"].(model.Map)["group"].(model.Map)["anyOf"]).To(Equal(requireChildren))
+		})
+
+		DescribeTable("accepts the fields which parser accepts",
+			func(getProperties func(schema model.Map) any, expected []string) {
+				properties := getProperties(schema).(model.Map)
+				keys := make([]string, 0, len(properties))
+				for key := range properties {
+					keys = append(keys, key)
+				}
+				Expect(keys).To(ConsistOf(expected))
+			},
+			Entry("spec",
+				func(schema model.Map) any { return schema["properties"] },
+				[]string{"spexec", "vars", "beforeAll", "beforeEach", "afterEach", "afterAll", "tests", "groups"}),
+			Entry("test",
+				func(schema model.Map) any { return schema["$defs"].(model.Map)["test"].(model.Map)["properties"] },
+				[]string{"name", "command", "stdin", "env", "dir", "expect", "timeout", "teeStdout", "teeStderr", "serial", "tags", "skip", "pending", "focus", "skipIf", "onlyIf", "each", "matrix"}),
+			Entry("group",
+				func(schema model.Map) any { return schema["$defs"].(model.Map)["group"].(model.Map)["properties"] },
+				[]string{"name", "vars", "env", "dir", "timeout", "tags", "beforeAll", "beforeEach", "afterEach", "afterAll", "tests", "groups"}),
+		)
+	})
+})
@@ -0,0 +1,80 @@
+// Copyright (C) 2021-2023	 Akira Tanimura (@autopp)
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// HasGlobMeta reports whether pattern contains the special characters of glob
+func HasGlobMeta(pattern string) bool {
+	return strings.ContainsAny(pattern, `*?[\`)
+}
+
+// MatchGlob reports whether name matches to the glob pattern.
+// In addition to the syntax of path.Match, "**" matches zero or more directories.
+func MatchGlob(pattern, name string) (bool, error) {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return false, err
+	}
+
+	return matchSegments(strings.Split(filepath.ToSlash(pattern), "/"), strings.Split(filepath.ToSlash(name), "/")), nil
+}
+
+func matchSegments(patterns, names []string) bool {
+	if len(patterns) == 0 {
+		return len(names) == 0
+	}
+
+	if patterns[0] == "**" {
+		for i := 0; i <= len(names); i++ {
+			if matchSegments(patterns[1:], names[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(names) == 0 {
+		return false
+	}
+
+	// pattern is already validated
+	matched, _ := path.Match(patterns[0], names[0])
+	return matched && matchSegments(patterns[1:], names[1:])
+}
+
+// GlobBase returns the leading directories of pattern which contain no special characters of glob
+func GlobBase(pattern string) string {
+	segments := strings.Split(filepath.ToSlash(pattern), "/")
+	base := make([]string, 0, len(segments))
+	for _, segment := range segments[:len(segments)-1] {
+		if HasGlobMeta(segment) {
+			break
+		}
+		base = append(base, segment)
+	}
+
+	if len(base) == 0 {
+		return "."
+	}
+	if len(base) == 1 && base[0] == "" {
+		return "/"
+	}
+
+	return filepath.FromSlash(strings.Join(base, "/"))
+}
@@ -0,0 +1,39 @@
+package util
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("MatchGlob", func() {
+	DescribeTable("matches name with pattern",
+		func(pattern, name string, expected bool) {
+			Expect(MatchGlob(pattern, name)).To(Equal(expected))
+		},
+		Entry("with literal", "specs/a.yaml", "specs/a.yaml", true),
+		Entry("with star", "specs/*.yaml", "specs/a.yaml", true),
+		Entry("with star not matching to directories", "specs/*.yaml", "specs/sub/a.yaml", false),
+		Entry("with double star matching to no directory", "specs/**/*.yaml", "specs/a.yaml", true),
+		Entry("with double star matching to directories", "specs/**/*.yaml", "specs/sub/dir/a.yaml", true),
+		Entry("with double star at last", "specs/**", "specs/sub/a.yaml", true),
+		Entry("with unmatched name", "specs/**/*.yaml", "other/a.yaml", false),
+	)
+
+	It("returns error with malformed pattern", func() {
+		_, err := MatchGlob("specs/[", "specs/a")
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("GlobBase", func() {
+	DescribeTable("returns the directories without special characters",
+		func(pattern, expected string) {
+			Expect(GlobBase(pattern)).To(Equal(expected))
+		},
+		Entry("with relative pattern", "specs/sub/**/*.yaml", "specs/sub"),
+		Entry("with no directory", "*.yaml", "."),
+		Entry("with pattern in directory", "specs/*/a.yaml", "specs"),
+		Entry("with absolute pattern", "/specs/*.yaml", "/specs"),
+		Entry("with root directory", "/*.yaml", "/"),
+	)
+})
@@ -0,0 +1,37 @@
+package util
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RelativePath", func() {
+	var wd string
+
+	BeforeEach(func() {
+		var err error
+		wd, err = os.Getwd()
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	DescribeTable("returns the path relative to the current directory only when the file is under it",
+		func(filename func() string, expected func() string) {
+			Expect(RelativePath(filename())).To(Equal(expected()))
+		},
+		Entry("with file under the current directory",
+			func() string { return filepath.Join(wd, "testdata", "spec.yaml") },
+			func() string { return filepath.Join("testdata", "spec.yaml") },
+		),
+		Entry("with file out of the current directory",
+			func() string { return filepath.Join(filepath.Dir(wd), "spec.yaml") },
+			func() string { return filepath.Join(filepath.Dir(wd), "spec.yaml") },
+		),
+		Entry("with relative path",
+			func() string { return "spec.yaml" },
+			func() string { return "spec.yaml" },
+		),
+	)
+})